@@ -0,0 +1,480 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 17. 泛型缓存
+// 支持LRU（最近最少使用）和LFU（最不经常使用）两种淘汰策略，以及按条目的过期时间
+// 使用RWMutex保证并发安全，淘汰回调在释放锁之后执行，回调中可以再次访问缓存
+
+// EvictionPolicy 缓存淘汰策略
+type EvictionPolicy int
+
+const (
+	LRU EvictionPolicy = iota // 淘汰最久未被访问的条目
+	LFU                       // 淘汰访问次数最少的条目，次数相同时淘汰最久未访问的
+)
+
+// EvictReason 条目被移出缓存的原因
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota // 超出容量被淘汰
+	EvictExpired                     // 过期
+	EvictDeleted                     // 被主动删除
+)
+
+// String 返回淘汰原因的描述
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "容量淘汰"
+	case EvictExpired:
+		return "过期"
+	case EvictDeleted:
+		return "删除"
+	}
+	return "未知"
+}
+
+// ErrNoLoader 未配置加载函数时调用GetOrLoad返回的错误
+var ErrNoLoader = errors.New("缓存未配置加载函数")
+
+// CacheOptions 缓存配置
+type CacheOptions[K comparable, V any] struct {
+	MaxSize int                                      // 最大条目数，0表示不限制
+	Policy  EvictionPolicy                           // 淘汰策略
+	TTL     time.Duration                            // 默认过期时间，0表示永不过期
+	OnEvict func(key K, value V, reason EvictReason) // 条目被移出时的回调
+	Loader  func(key K) (V, error)                   // 未命中时的加载函数
+}
+
+// CacheStats 缓存统计信息
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRate 返回命中率
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// cacheEntry 缓存条目
+type cacheEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	expireAt time.Time // 零值表示永不过期
+	freq     int       // 访问次数，仅LFU使用
+	node     *Node[*cacheEntry[K, V]]
+}
+
+// expired 检查条目是否已过期
+func (e *cacheEntry[K, V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// evicted 等待回调的被淘汰条目
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// loadCall 正在进行的加载，同一个键的并发加载共享同一次调用
+type loadCall[V any] struct {
+	wg         sync.WaitGroup
+	value      V
+	err        error
+	superseded bool // 加载期间键被写入或删除，加载结果不再写入缓存，受写锁保护
+}
+
+// Cache 并发安全的泛型缓存，请使用NewCache创建，零值只能用于反序列化
+type Cache[K comparable, V any] struct {
+	mu      sync.RWMutex
+	opts    CacheOptions[K, V]
+	entries map[K]*cacheEntry[K, V]
+	stats   CacheStats
+
+	// LRU：链表头部是最近访问的条目
+	recency *LinkedList[*cacheEntry[K, V]]
+
+	// LFU：每个访问次数对应一个按最近访问排序的链表
+	freqs   map[int]*LinkedList[*cacheEntry[K, V]]
+	minFreq int
+
+	loading map[K]*loadCall[V]
+}
+
+// NewCache 创建缓存
+func NewCache[K comparable, V any](opts CacheOptions[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{opts: opts}
+	c.reset()
+	return c
+}
+
+// reset 清空所有条目，统计信息和正在进行的加载不受影响，调用方需持有写锁
+func (c *Cache[K, V]) reset() {
+	c.entries = make(map[K]*cacheEntry[K, V])
+	c.minFreq = 0
+	if c.opts.Policy == LFU {
+		c.freqs = make(map[int]*LinkedList[*cacheEntry[K, V]])
+		c.recency = nil
+	} else {
+		c.freqs = nil
+		c.recency = NewLinkedList[*cacheEntry[K, V]]()
+	}
+	if c.loading == nil {
+		c.loading = make(map[K]*loadCall[V])
+	}
+}
+
+// Set 写入条目，使用默认过期时间
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL 写入条目并指定过期时间，ttl为0表示永不过期
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	c.supersedeLoad(key)
+	out := c.set(key, value, ttl)
+	c.mu.Unlock()
+	c.notify(out)
+}
+
+// Get 读取条目，过期的条目视为未命中
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	value, ok, out := c.get(key)
+	c.mu.Unlock()
+	c.notify(out)
+	return value, ok
+}
+
+// Peek 读取条目但不更新访问顺序和统计信息
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Delete 删除条目，返回条目是否存在
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	c.supersedeLoad(key)
+	entry, ok := c.entries[key]
+	var out []evicted[K, V]
+	if ok {
+		c.remove(entry)
+		out = append(out, evicted[K, V]{key: key, value: entry.value, reason: EvictDeleted})
+	}
+	c.mu.Unlock()
+	c.notify(out)
+	return ok
+}
+
+// GetOrLoad 读取条目，未命中时调用加载函数并写入缓存
+// 同一个键的并发未命中只会调用一次加载函数，加载失败不会被缓存
+// 加载期间该键被Set或Delete时，加载到的旧值只返回给调用方，不会覆盖新的写入
+func (c *Cache[K, V]) GetOrLoad(key K) (V, error) {
+	if c.opts.Loader == nil {
+		var zero V
+		return zero, ErrNoLoader
+	}
+
+	c.mu.Lock()
+	value, ok, out := c.get(key)
+	if ok {
+		c.mu.Unlock()
+		c.notify(out)
+		return value, nil
+	}
+	if call, ok := c.loading[key]; ok {
+		// 已有goroutine在加载该键，等待它的结果
+		c.mu.Unlock()
+		c.notify(out)
+		call.wg.Wait()
+		return call.value, call.err
+	}
+	call := &loadCall[V]{}
+	call.wg.Add(1)
+	c.loading[key] = call
+	c.mu.Unlock()
+	c.notify(out)
+
+	call.value, call.err = c.load(key)
+
+	c.mu.Lock()
+	delete(c.loading, key)
+	out = nil
+	if call.err == nil && !call.superseded {
+		out = c.set(key, call.value, c.opts.TTL)
+	}
+	c.mu.Unlock()
+	call.wg.Done()
+	c.notify(out)
+	return call.value, call.err
+}
+
+// supersedeLoad 键被写入或删除时，让正在进行的加载放弃写入，调用方需持有写锁
+func (c *Cache[K, V]) supersedeLoad(key K) {
+	if call, ok := c.loading[key]; ok {
+		call.superseded = true
+	}
+}
+
+// load 调用加载函数，将panic转换为错误，保证等待者不会永远阻塞
+func (c *Cache[K, V]) load(key K) (value V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("加载键 %v 时发生panic: %v", key, r)
+		}
+	}()
+	return c.opts.Loader(key)
+}
+
+// DeleteExpired 清理所有已过期的条目，返回清理的数量
+func (c *Cache[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	now := time.Now()
+	var out []evicted[K, V]
+	for _, entry := range c.entries {
+		if entry.expired(now) {
+			c.remove(entry)
+			c.stats.Evictions++
+			out = append(out, evicted[K, V]{key: entry.key, value: entry.value, reason: EvictExpired})
+		}
+	}
+	c.mu.Unlock()
+	c.notify(out)
+	return len(out)
+}
+
+// Len 返回缓存中的条目数，可能包含尚未清理的过期条目
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Stats 返回统计信息的快照
+func (c *Cache[K, V]) Stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stats
+}
+
+// notify 在锁外执行淘汰回调
+func (c *Cache[K, V]) notify(out []evicted[K, V]) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, e := range out {
+		c.opts.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+// get 在持有写锁时读取条目
+func (c *Cache[K, V]) get(key K) (V, bool, []evicted[K, V]) {
+	var zero V
+	entry, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return zero, false, nil
+	}
+	if entry.expired(time.Now()) {
+		c.remove(entry)
+		c.stats.Misses++
+		c.stats.Evictions++
+		return zero, false, []evicted[K, V]{{key: key, value: entry.value, reason: EvictExpired}}
+	}
+	c.stats.Hits++
+	c.touch(entry)
+	return entry.value, true, nil
+}
+
+// set 在持有写锁时写入条目，返回因此被淘汰的条目
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration) []evicted[K, V] {
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	if entry, ok := c.entries[key]; ok {
+		entry.value = value
+		entry.expireAt = expireAt
+		c.touch(entry)
+		return nil
+	}
+
+	var out []evicted[K, V]
+	if c.opts.MaxSize > 0 && len(c.entries) >= c.opts.MaxSize {
+		victim := c.victim()
+		reason := EvictCapacity
+		if victim.expired(time.Now()) {
+			reason = EvictExpired
+		}
+		c.remove(victim)
+		c.stats.Evictions++
+		out = append(out, evicted[K, V]{key: victim.key, value: victim.value, reason: reason})
+	}
+
+	entry := &cacheEntry[K, V]{key: key, value: value, expireAt: expireAt}
+	c.entries[key] = entry
+	if c.opts.Policy == LFU {
+		entry.freq = 1
+		entry.node = c.freqList(1).PushFront(entry)
+		c.minFreq = 1
+	} else {
+		entry.node = c.recency.PushFront(entry)
+	}
+	return out
+}
+
+// touch 记录一次访问
+func (c *Cache[K, V]) touch(entry *cacheEntry[K, V]) {
+	if c.opts.Policy != LFU {
+		c.recency.MoveToFront(entry.node)
+		return
+	}
+
+	list := c.freqs[entry.freq]
+	list.Remove(entry.node)
+	if list.IsEmpty() {
+		delete(c.freqs, entry.freq)
+		if c.minFreq == entry.freq {
+			c.minFreq++
+		}
+	}
+	entry.freq++
+	entry.node = c.freqList(entry.freq).PushFront(entry)
+}
+
+// victim 按淘汰策略选出要淘汰的条目
+func (c *Cache[K, V]) victim() *cacheEntry[K, V] {
+	if c.opts.Policy == LFU {
+		return c.freqs[c.minFreq].Back().Value()
+	}
+	return c.recency.Back().Value()
+}
+
+// remove 从所有内部结构中移除条目
+func (c *Cache[K, V]) remove(entry *cacheEntry[K, V]) {
+	delete(c.entries, entry.key)
+	if c.opts.Policy != LFU {
+		c.recency.Remove(entry.node)
+		return
+	}
+
+	list := c.freqs[entry.freq]
+	list.Remove(entry.node)
+	if list.IsEmpty() {
+		delete(c.freqs, entry.freq)
+		if c.minFreq == entry.freq {
+			c.recomputeMinFreq()
+		}
+	}
+}
+
+// recomputeMinFreq 重新计算最小访问次数，只在删除最小次数的最后一个条目时调用
+func (c *Cache[K, V]) recomputeMinFreq() {
+	c.minFreq = 0
+	for freq := range c.freqs {
+		if c.minFreq == 0 || freq < c.minFreq {
+			c.minFreq = freq
+		}
+	}
+}
+
+// freqList 返回访问次数对应的链表，不存在时创建
+func (c *Cache[K, V]) freqList(freq int) *LinkedList[*cacheEntry[K, V]] {
+	list, ok := c.freqs[freq]
+	if !ok {
+		list = NewLinkedList[*cacheEntry[K, V]]()
+		c.freqs[freq] = list
+	}
+	return list
+}
+
+// 17.1 泛型缓存演示
+func demonstrateCache() {
+	fmt.Println("\n=== 泛型缓存演示 ===")
+
+	onEvict := func(key string, value int, reason EvictReason) {
+		fmt.Printf("  淘汰 %s=%d (%s)\n", key, value, reason)
+	}
+
+	fmt.Println("LRU缓存 (容量3):")
+	lru := NewCache(CacheOptions[string, int]{MaxSize: 3, Policy: LRU, OnEvict: onEvict})
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.Set("c", 3)
+	lru.Get("a") // a变为最近访问
+	lru.Set("d", 4)
+
+	fmt.Println("LFU缓存 (容量3):")
+	lfu := NewCache(CacheOptions[string, int]{MaxSize: 3, Policy: LFU, OnEvict: onEvict})
+	lfu.Set("a", 1)
+	lfu.Set("b", 2)
+	lfu.Set("c", 3)
+	lfu.Get("a")
+	lfu.Get("a")
+	lfu.Get("b")
+	lfu.Set("d", 4)
+
+	fmt.Println("TTL过期:")
+	ttl := NewCache(CacheOptions[string, int]{TTL: 50 * time.Millisecond, OnEvict: onEvict})
+	ttl.Set("session", 42)
+	ttl.SetWithTTL("config", 7, 0)
+	time.Sleep(80 * time.Millisecond)
+	_, ok := ttl.Get("session")
+	fmt.Printf("  session是否命中: %v\n", ok)
+	v, ok := ttl.Get("config")
+	fmt.Printf("  config是否命中: %v, 值: %d\n", ok, v)
+
+	// 加载函数模式：并发未命中同一个键只加载一次
+	var mu sync.Mutex
+	loads := 0
+	loader := NewCache(CacheOptions[int, string]{
+		MaxSize: 100,
+		Loader: func(id int) (string, error) {
+			mu.Lock()
+			loads++
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			return fmt.Sprintf("用户%d", id), nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loader.GetOrLoad(1)
+		}()
+	}
+	wg.Wait()
+	name, _ := loader.GetOrLoad(1)
+	fmt.Printf("加载函数: %s, 调用次数: %d\n", name, loads)
+
+	stats := loader.Stats()
+	fmt.Printf("统计: 命中=%d, 未命中=%d, 淘汰=%d, 命中率=%.2f\n",
+		stats.Hits, stats.Misses, stats.Evictions, stats.HitRate())
+}
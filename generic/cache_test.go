@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheEvictionPolicies(t *testing.T) {
+	tests := []struct {
+		policy  EvictionPolicy
+		victim  string
+		survive []string
+	}{
+		// a被访问过，LRU淘汰最久未访问的b
+		{LRU, "b", []string{"a", "c", "d"}},
+		// a被访问2次，b和c各1次，LFU淘汰次数最少中最久未访问的b
+		{LFU, "b", []string{"a", "c", "d"}},
+	}
+	for _, tt := range tests {
+		type ev struct {
+			key    string
+			reason EvictReason
+		}
+		var evicted []ev
+		c := NewCache(CacheOptions[string, int]{
+			MaxSize: 3,
+			Policy:  tt.policy,
+			OnEvict: func(k string, _ int, r EvictReason) { evicted = append(evicted, ev{k, r}) },
+		})
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Set("c", 3)
+		c.Get("a")
+		c.Get("c")
+		c.Get("a")
+		c.Set("d", 4)
+
+		if len(evicted) != 1 || evicted[0] != (ev{tt.victim, EvictCapacity}) {
+			t.Fatalf("policy %d evicted %v, want %s", tt.policy, evicted, tt.victim)
+		}
+		for _, k := range tt.survive {
+			if _, ok := c.Peek(k); !ok {
+				t.Errorf("policy %d: %s was evicted", tt.policy, k)
+			}
+		}
+		if c.Len() != 3 {
+			t.Errorf("Len() = %d, want 3", c.Len())
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	var reasons []EvictReason
+	c := NewCache(CacheOptions[string, int]{
+		TTL:     20 * time.Millisecond,
+		OnEvict: func(_ string, _ int, r EvictReason) { reasons = append(reasons, r) },
+	})
+	c.Set("short", 1)
+	c.SetWithTTL("forever", 2, 0)
+	c.Set("gone", 3)
+	if _, ok := c.Get("short"); !ok {
+		t.Fatal("entry expired too early")
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := c.Peek("short"); ok {
+		t.Error("Peek() returned an expired entry")
+	}
+	if _, ok := c.Get("short"); ok {
+		t.Error("Get() returned an expired entry")
+	}
+	if n := c.DeleteExpired(); n != 1 {
+		t.Errorf("DeleteExpired() = %d, want 1", n)
+	}
+	if v, ok := c.Get("forever"); !ok || v != 2 {
+		t.Errorf("Get(forever) = %d, %v", v, ok)
+	}
+	if !c.Delete("forever") || c.Delete("forever") {
+		t.Error("Delete() result wrong")
+	}
+	want := []EvictReason{EvictExpired, EvictExpired, EvictDeleted}
+	if len(reasons) != len(want) {
+		t.Fatalf("eviction reasons = %v, want %v", reasons, want)
+	}
+	for i := range want {
+		if reasons[i] != want[i] {
+			t.Fatalf("eviction reasons = %v, want %v", reasons, want)
+		}
+	}
+
+	s := c.Stats()
+	if s.Hits != 2 || s.Misses != 1 || s.Evictions != 2 {
+		t.Errorf("Stats() = %+v", s)
+	}
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := NewCache(CacheOptions[string, string]{
+		Loader: func(k string) (string, error) {
+			calls.Add(1)
+			switch k {
+			case "bad":
+				return "", errors.New("not found")
+			case "panic":
+				panic("boom")
+			}
+			<-release
+			return strings.ToUpper(k), nil
+		},
+	})
+
+	// 同一个键的并发未命中只加载一次
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = c.GetOrLoad("key")
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, r := range results {
+		if r != "KEY" {
+			t.Fatalf("GetOrLoad() = %q, want KEY", r)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+
+	// 加载失败不缓存
+	for range 2 {
+		if _, err := c.GetOrLoad("bad"); err == nil {
+			t.Error("GetOrLoad(bad) returned nil error")
+		}
+	}
+	if _, err := c.GetOrLoad("panic"); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("GetOrLoad(panic) = %v", err)
+	}
+	if n := calls.Load(); n != 4 {
+		t.Errorf("loader called %d times, want 4", n)
+	}
+
+	if _, err := NewCache(CacheOptions[int, int]{}).GetOrLoad(1); !errors.Is(err, ErrNoLoader) {
+		t.Errorf("GetOrLoad without loader = %v", err)
+	}
+}
+
+func TestCacheLoadSuperseded(t *testing.T) {
+	// 加载期间的Set和Delete比加载结果新，加载完成后不能被旧值覆盖
+	tests := []struct {
+		name   string
+		write  func(c *Cache[string, string])
+		want   string
+		wantOK bool
+	}{
+		{"加载期间Set", func(c *Cache[string, string]) { c.Set("k", "new") }, "new", true},
+		{"加载期间Delete", func(c *Cache[string, string]) { c.Delete("k") }, "", false},
+		{"加载期间反序列化", func(c *Cache[string, string]) {
+			if err := c.UnmarshalJSON([]byte(`[{"key":"other","value":"v"}]`)); err != nil {
+				t.Fatal(err)
+			}
+		}, "", false},
+		{"写入其他键", func(c *Cache[string, string]) { c.Set("other", "v") }, "loaded", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started, release := make(chan struct{}), make(chan struct{})
+			c := NewCache(CacheOptions[string, string]{
+				Loader: func(string) (string, error) {
+					close(started)
+					<-release
+					return "loaded", nil
+				},
+			})
+			done := make(chan string)
+			go func() {
+				v, _ := c.GetOrLoad("k")
+				done <- v
+			}()
+			<-started
+			tt.write(c)
+			close(release)
+			// 调用方仍然拿到它加载的值
+			if v := <-done; v != "loaded" {
+				t.Errorf("GetOrLoad() = %q, want loaded", v)
+			}
+			if v, ok := c.Peek("k"); v != tt.want || ok != tt.wantOK {
+				t.Errorf("Peek(k) = %q, %v, want %q, %v", v, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCacheConcurrentAccess(t *testing.T) {
+	for _, policy := range []EvictionPolicy{LRU, LFU} {
+		c := NewCache(CacheOptions[int, int]{MaxSize: 50, Policy: policy})
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 2000 {
+					k := (i * (g + 1)) % 100
+					switch i % 4 {
+					case 0:
+						c.Set(k, i)
+					case 1:
+						c.Delete(k)
+					default:
+						c.Get(k)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		if c.Len() > 50 {
+			t.Errorf("policy %d: Len() = %d exceeds MaxSize", policy, c.Len())
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// 35. 溢出检查的整数运算
+// Add和SumNumbers溢出时会静默回绕（例如int8的127+1变成-128），金额计数之类的场景不能接受
+// Checked系列在溢出时返回错误，Saturating系列把结果截断到类型的最小/最大值，SafeConvert检查类型转换是否丢失数值
+
+var (
+	// ErrOverflow 运算结果超出类型的表示范围
+	ErrOverflow = errors.New("整数溢出")
+	// ErrDivisionByZero 除数为零
+	ErrDivisionByZero = errors.New("除数为零")
+	// ErrTruncated 类型转换后数值发生变化
+	ErrTruncated = errors.New("类型转换丢失数值")
+)
+
+// isSigned 判断T是否为有符号整数：有符号类型的^0是-1
+func isSigned[T Integer]() bool {
+	return ^T(0) < 0
+}
+
+// integerBounds 返回T能表示的最小值和最大值
+func integerBounds[T Integer]() (lo, hi T) {
+	if !isSigned[T]() {
+		return 0, ^T(0)
+	}
+	bits := unsafe.Sizeof(hi) * 8
+	hi = T(uint64(math.MaxUint64) >> (64 - bits + 1))
+	return ^hi, hi
+}
+
+// CheckedAdd 返回a+b，溢出时返回ErrOverflow
+func CheckedAdd[T Integer](a, b T) (T, error) {
+	r := a + b
+	// 加正数结果却变小、加负数结果却变大说明发生了回绕
+	if (b > 0 && r < a) || (b < 0 && r > a) {
+		return r, fmt.Errorf("%v + %v: %w", a, b, ErrOverflow)
+	}
+	return r, nil
+}
+
+// CheckedSub 返回a-b，溢出时返回ErrOverflow
+func CheckedSub[T Integer](a, b T) (T, error) {
+	r := a - b
+	if (b > 0 && r > a) || (b < 0 && r < a) {
+		return r, fmt.Errorf("%v - %v: %w", a, b, ErrOverflow)
+	}
+	return r, nil
+}
+
+// CheckedMul 返回a*b，溢出时返回ErrOverflow
+func CheckedMul[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	r := a * b
+	// 最小值乘以-1会回绕成自身，而最小值除以-1同样得到自身，只靠r/b != a检查不出来
+	overflow := r/b != a
+	if isSigned[T]() {
+		lo, _ := integerBounds[T]()
+		overflow = overflow || (a == lo && b == ^T(0)) || (b == lo && a == ^T(0))
+	}
+	if overflow {
+		return r, fmt.Errorf("%v * %v: %w", a, b, ErrOverflow)
+	}
+	return r, nil
+}
+
+// CheckedDiv 返回a/b（向零取整），除数为零时返回ErrDivisionByZero，最小值除以-1时返回ErrOverflow
+func CheckedDiv[T Integer](a, b T) (T, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("%v / %v: %w", a, b, ErrDivisionByZero)
+	}
+	if isSigned[T]() {
+		if lo, _ := integerBounds[T](); a == lo && b == ^T(0) {
+			return a, fmt.Errorf("%v / %v: %w", a, b, ErrOverflow)
+		}
+	}
+	return a / b, nil
+}
+
+// CheckedSum 对切片求和，任何一步溢出都返回ErrOverflow
+func CheckedSum[T Integer](nums []T) (T, error) {
+	var sum T
+	for _, num := range nums {
+		var err error
+		if sum, err = CheckedAdd(sum, num); err != nil {
+			return sum, err
+		}
+	}
+	return sum, nil
+}
+
+// SaturatingAdd 返回a+b，溢出时返回T的最大值或最小值
+func SaturatingAdd[T Integer](a, b T) T {
+	r, err := CheckedAdd(a, b)
+	if err == nil {
+		return r
+	}
+	lo, hi := integerBounds[T]()
+	if b > 0 {
+		return hi
+	}
+	return lo
+}
+
+// SaturatingSub 返回a-b，溢出时返回T的最大值或最小值，无符号类型不会小于0
+func SaturatingSub[T Integer](a, b T) T {
+	r, err := CheckedSub(a, b)
+	if err == nil {
+		return r
+	}
+	lo, hi := integerBounds[T]()
+	if b > 0 {
+		return lo
+	}
+	return hi
+}
+
+// SaturatingMul 返回a*b，溢出时根据结果的符号返回T的最大值或最小值
+func SaturatingMul[T Integer](a, b T) T {
+	r, err := CheckedMul(a, b)
+	if err == nil {
+		return r
+	}
+	lo, hi := integerBounds[T]()
+	if (a < 0) != (b < 0) {
+		return lo
+	}
+	return hi
+}
+
+// SaturatingDiv 返回a/b，最小值除以-1时返回最大值，除数为零时和普通除法一样panic
+func SaturatingDiv[T Integer](a, b T) T {
+	r, err := CheckedDiv(a, b)
+	if errors.Is(err, ErrDivisionByZero) {
+		panic("SaturatingDiv: 除数为零")
+	}
+	if err != nil {
+		_, hi := integerBounds[T]()
+		return hi
+	}
+	return r
+}
+
+// SafeConvert 将整数转换为另一种整数类型，数值无法原样表示时返回ErrTruncated
+func SafeConvert[From, To Integer](v From) (To, error) {
+	r := To(v)
+	// 转换回来不相等说明高位被截断；符号不同说明负数和无符号数之间发生了重新解释（如int8(-1)变成uint8(255)）
+	if From(r) != v || (v < 0) != (r < 0) {
+		return r, fmt.Errorf("%v 转换为 %T: %w", v, r, ErrTruncated)
+	}
+	return r, nil
+}
+
+// 35.1 溢出检查演示
+func demonstrateChecked() {
+	fmt.Println("\n=== 溢出检查的整数运算演示 ===")
+
+	var i8 int8 = 127
+	fmt.Printf("Add(int8(127), 1) = %d（静默回绕）\n", Add(i8, 1))
+	if _, err := CheckedAdd(i8, 1); err != nil {
+		fmt.Printf("CheckedAdd: %v\n", err)
+	}
+	fmt.Printf("SaturatingAdd(int8(127), 1) = %d, SaturatingSub(uint8(3), 5) = %d\n",
+		SaturatingAdd(i8, 1), SaturatingSub(uint8(3), 5))
+
+	// 以分为单位的账户余额
+	type Cents int64
+	balance := Cents(math.MaxInt64 - 100)
+	for _, deposit := range []Cents{50, 50, 1} {
+		next, err := CheckedAdd(balance, deposit)
+		if err != nil {
+			fmt.Printf("存入 %d 分被拒绝: %v\n", deposit, err)
+			break
+		}
+		balance = next
+	}
+	fmt.Printf("余额: %d 分\n", balance)
+
+	if _, err := CheckedSum([]uint16{40000, 20000, 10000}); err != nil {
+		fmt.Printf("CheckedSum: %v\n", err)
+	}
+	if _, err := CheckedMul(int32(math.MinInt32), -1); err != nil {
+		fmt.Printf("CheckedMul: %v\n", err)
+	}
+	if _, err := CheckedDiv(10, 0); errors.Is(err, ErrDivisionByZero) {
+		fmt.Printf("CheckedDiv: %v\n", err)
+	}
+
+	for _, v := range []int64{1000, 1 << 40, -1} {
+		if r, err := SafeConvert[int64, uint32](v); err != nil {
+			fmt.Printf("SafeConvert: %v\n", err)
+		} else {
+			fmt.Printf("SafeConvert(%d) = %d\n", v, r)
+		}
+	}
+}
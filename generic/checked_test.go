@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// toBig 将任意宽度的整数精确转换为big.Int
+func toBig[T Integer](v T) *big.Int {
+	if isSigned[T]() {
+		return big.NewInt(int64(v))
+	}
+	return new(big.Int).SetUint64(uint64(v))
+}
+
+// fitsIn 判断x能否用T表示
+func fitsIn[T Integer](x *big.Int) bool {
+	lo, hi := integerBounds[T]()
+	return x.Cmp(toBig(lo)) >= 0 && x.Cmp(toBig(hi)) <= 0
+}
+
+// clampTo 将x截断到T的取值范围，是饱和运算的参照结果
+func clampTo[T Integer](x *big.Int) *big.Int {
+	lo, hi := integerBounds[T]()
+	if x.Cmp(toBig(lo)) < 0 {
+		return toBig(lo)
+	}
+	if x.Cmp(toBig(hi)) > 0 {
+		return toBig(hi)
+	}
+	return x
+}
+
+// checkArithmetic 用big.Int的精确结果检查a和b的Checked和Saturating运算
+func checkArithmetic[T Integer](t *testing.T, a, b T) {
+	t.Helper()
+	ops := []struct {
+		name      string
+		checked   func(T, T) (T, error)
+		saturated func(T, T) T
+		exact     func(x, y *big.Int) *big.Int
+	}{
+		{"Add", CheckedAdd[T], SaturatingAdd[T], new(big.Int).Add},
+		{"Sub", CheckedSub[T], SaturatingSub[T], new(big.Int).Sub},
+		{"Mul", CheckedMul[T], SaturatingMul[T], new(big.Int).Mul},
+		{"Div", CheckedDiv[T], SaturatingDiv[T], new(big.Int).Quo},
+	}
+	for _, op := range ops {
+		if op.name == "Div" && b == 0 {
+			if _, err := op.checked(a, b); !errors.Is(err, ErrDivisionByZero) {
+				t.Fatalf("CheckedDiv[%T](%v, 0) = %v", a, a, err)
+			}
+			continue
+		}
+		want := op.exact(toBig(a), toBig(b))
+		got, err := op.checked(a, b)
+		if fitsIn[T](want) {
+			if err != nil || toBig(got).Cmp(want) != 0 {
+				t.Fatalf("Checked%s[%T](%v, %v) = %v, %v, want %v", op.name, a, a, b, got, err, want)
+			}
+		} else if !errors.Is(err, ErrOverflow) {
+			t.Fatalf("Checked%s[%T](%v, %v) = %v, %v, want ErrOverflow", op.name, a, a, b, got, err)
+		}
+		if got, want := op.saturated(a, b), clampTo[T](want); toBig(got).Cmp(want) != 0 {
+			t.Fatalf("Saturating%s[%T](%v, %v) = %v, want %v", op.name, a, a, b, got, want)
+		}
+	}
+}
+
+// interestingValue 一半概率返回边界附近的值，一半概率返回随机位模式
+func interestingValue[T Integer](r *rand.Rand) T {
+	lo, hi := integerBounds[T]()
+	candidates := []T{lo, lo + 1, lo / 2, hi, hi - 1, hi / 2, 0, 1, 2, ^T(0)}
+	if r.Intn(2) == 0 {
+		return candidates[r.Intn(len(candidates))]
+	}
+	return T(r.Uint64() >> r.Intn(64))
+}
+
+// checkRandomArithmetic 对T做n次随机的运算检查
+func checkRandomArithmetic[T Integer](t *testing.T, r *rand.Rand, n int) {
+	t.Helper()
+	for range n {
+		checkArithmetic(t, interestingValue[T](r), interestingValue[T](r))
+	}
+}
+
+// balance 自定义的整数类型，检查~约束
+type balance int64
+
+func TestCheckedArithmeticExhaustive(t *testing.T) {
+	// 8位整数可以穷举所有操作数组合
+	for a := -128; a < 128; a++ {
+		for b := -128; b < 128; b++ {
+			checkArithmetic(t, int8(a), int8(b))
+		}
+	}
+	for a := 0; a < 256; a++ {
+		for b := 0; b < 256; b++ {
+			checkArithmetic(t, uint8(a), uint8(b))
+		}
+	}
+}
+
+func TestCheckedArithmeticRandom(t *testing.T) {
+	n := 50000
+	if testing.Short() {
+		n = 2000
+	}
+	tests := []struct {
+		name string
+		run  func(t *testing.T, r *rand.Rand)
+	}{
+		{"int", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[int](t, r, n) }},
+		{"int16", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[int16](t, r, n) }},
+		{"int32", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[int32](t, r, n) }},
+		{"int64", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[int64](t, r, n) }},
+		{"uint", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[uint](t, r, n) }},
+		{"uint16", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[uint16](t, r, n) }},
+		{"uint32", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[uint32](t, r, n) }},
+		{"uint64", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[uint64](t, r, n) }},
+		{"uintptr", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[uintptr](t, r, n) }},
+		{"自定义类型", func(t *testing.T, r *rand.Rand) { checkRandomArithmetic[balance](t, r, n) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, rand.New(rand.NewSource(1)))
+		})
+	}
+}
+
+// checkConvert 检查v转换为To的结果：能表示时数值不变，否则返回ErrTruncated
+func checkConvert[From, To Integer](t *testing.T, v From) {
+	t.Helper()
+	got, err := SafeConvert[From, To](v)
+	if fitsIn[To](toBig(v)) {
+		if err != nil || toBig(got).Cmp(toBig(v)) != 0 {
+			t.Fatalf("SafeConvert[%T, %T](%v) = %v, %v", v, got, v, got, err)
+		}
+	} else if !errors.Is(err, ErrTruncated) {
+		t.Fatalf("SafeConvert[%T, %T](%v) = %v, %v, want ErrTruncated", v, got, v, got, err)
+	}
+}
+
+// checkConvertFrom 把From类型的随机值转换到所有整数类型
+func checkConvertFrom[From Integer](t *testing.T, r *rand.Rand) {
+	t.Helper()
+	for range 5000 {
+		v := interestingValue[From](r)
+		checkConvert[From, int](t, v)
+		checkConvert[From, int8](t, v)
+		checkConvert[From, int16](t, v)
+		checkConvert[From, int32](t, v)
+		checkConvert[From, int64](t, v)
+		checkConvert[From, uint](t, v)
+		checkConvert[From, uint8](t, v)
+		checkConvert[From, uint16](t, v)
+		checkConvert[From, uint32](t, v)
+		checkConvert[From, uint64](t, v)
+		checkConvert[From, uintptr](t, v)
+		checkConvert[From, balance](t, v)
+	}
+}
+
+func TestSafeConvert(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	checkConvertFrom[int](t, r)
+	checkConvertFrom[int8](t, r)
+	checkConvertFrom[int16](t, r)
+	checkConvertFrom[int32](t, r)
+	checkConvertFrom[int64](t, r)
+	checkConvertFrom[uint](t, r)
+	checkConvertFrom[uint8](t, r)
+	checkConvertFrom[uint16](t, r)
+	checkConvertFrom[uint32](t, r)
+	checkConvertFrom[uint64](t, r)
+	checkConvertFrom[uintptr](t, r)
+	checkConvertFrom[balance](t, r)
+}
+
+func TestCheckedSum(t *testing.T) {
+	tests := []struct {
+		name string
+		nums []int8
+		want int8
+		err  error
+	}{
+		{"空切片", nil, 0, nil},
+		{"不溢出", []int8{100, 27, -50}, 77, nil},
+		{"中途溢出", []int8{100, 100, -100}, 0, ErrOverflow},
+		{"负向溢出", []int8{-100, -29}, 0, ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckedSum(tt.nums)
+			if !errors.Is(err, tt.err) || err == nil && got != tt.want {
+				t.Errorf("CheckedSum(%v) = %d, %v, want %d, %v", tt.nums, got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// 32. 集合操作辅助函数
+// 每个函数都有切片版本和iter.Seq版本（Seq后缀），与Map/MapSeq的约定一致
+// 返回序列的Seq版本是惰性的；返回map、切片或单个值的Seq版本会消费整个序列（Find/Any/All遇到结果即停止）
+// 切片版本的Chunk和Window返回原切片的子切片以避免复制，子切片的容量被截断，对它们append不会覆盖原切片
+
+// GroupBy 按key分组，每组内保持原来的顺序
+func GroupBy[T any, K comparable](slice []T, key func(T) K) map[K][]T {
+	return GroupBySeq(slices.Values(slice), key)
+}
+
+// GroupBySeq 按key对序列分组
+func GroupBySeq[T any, K comparable](seq iter.Seq[T], key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for v := range seq {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// CountBy 按key统计元素个数
+func CountBy[T any, K comparable](slice []T, key func(T) K) map[K]int {
+	return CountBySeq(slices.Values(slice), key)
+}
+
+// CountBySeq 按key统计序列中的元素个数
+func CountBySeq[T any, K comparable](seq iter.Seq[T], key func(T) K) map[K]int {
+	counts := make(map[K]int)
+	for v := range seq {
+		counts[key(v)]++
+	}
+	return counts
+}
+
+// Partition 按predicate将元素分成满足和不满足的两组，各自保持原来的顺序
+func Partition[T any](slice []T, predicate func(T) bool) (matched, rest []T) {
+	return PartitionSeq(slices.Values(slice), predicate)
+}
+
+// PartitionSeq 按predicate将序列分成满足和不满足的两组
+func PartitionSeq[T any](seq iter.Seq[T], predicate func(T) bool) (matched, rest []T) {
+	for v := range seq {
+		if predicate(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// Chunk 将切片按size分块，最后一块可能不足size个
+func Chunk[T any](slice []T, size int) [][]T {
+	if size < 1 {
+		panic("Chunk: size必须大于0")
+	}
+	chunks := make([][]T, 0, (len(slice)+size-1)/size)
+	for i := 0; i < len(slice); i += size {
+		end := min(i+size, len(slice))
+		chunks = append(chunks, slice[i:end:end])
+	}
+	return chunks
+}
+
+// Window 返回所有长度为size的滑动窗口，切片长度小于size时返回空
+func Window[T any](slice []T, size int) [][]T {
+	if size < 1 {
+		panic("Window: size必须大于0")
+	}
+	var windows [][]T
+	for i := 0; i+size <= len(slice); i++ {
+		windows = append(windows, slice[i:i+size:i+size])
+	}
+	return windows
+}
+
+// WindowSeq 惰性滑动窗口：每次产生最近size个元素组成的新切片
+func WindowSeq[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("WindowSeq: size必须大于0")
+	}
+	return func(yield func([]T) bool) {
+		window := make([]T, 0, size)
+		for v := range seq {
+			if len(window) == size {
+				window = append(window[:0], window[1:]...)
+			}
+			window = append(window, v)
+			if len(window) == size && !yield(slices.Clone(window)) {
+				return
+			}
+		}
+	}
+}
+
+// Zip 将两个切片按位置配对，长度取较短的一个
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	pairs := make([]Pair[A, B], min(len(a), len(b)))
+	for i := range pairs {
+		pairs[i] = Pair[A, B]{Key: a[i], Value: b[i]}
+	}
+	return pairs
+}
+
+// Unzip 将键值对拆成两个切片，是Zip的逆操作
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	keys := make([]A, len(pairs))
+	values := make([]B, len(pairs))
+	for i, p := range pairs {
+		keys[i], values[i] = p.Key, p.Value
+	}
+	return keys, values
+}
+
+// UnzipSeq 将键值对序列拆成两个惰性序列，每个序列被遍历时都会重新遍历一次seq
+func UnzipSeq[A, B any](seq iter.Seq[Pair[A, B]]) (iter.Seq[A], iter.Seq[B]) {
+	keys := MapSeq(seq, func(p Pair[A, B]) A { return p.Key })
+	values := MapSeq(seq, func(p Pair[A, B]) B { return p.Value })
+	return keys, values
+}
+
+// Distinct 去除重复元素，保留每个元素第一次出现的位置
+func Distinct[T comparable](slice []T) []T {
+	return slices.Collect(DistinctSeq(slices.Values(slice)))
+}
+
+// DistinctSeq 惰性去重，需要记住已经出现过的元素
+func DistinctSeq[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[T]struct{})
+		for v := range seq {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Flatten 将二维切片按顺序展开为一维
+func Flatten[T any](nested [][]T) []T {
+	n := 0
+	for _, s := range nested {
+		n += len(s)
+	}
+	return slices.AppendSeq(make([]T, 0, n), FlattenSeq(slices.Values(nested)))
+}
+
+// FlattenSeq 惰性展开切片序列
+func FlattenSeq[T any](seq iter.Seq[[]T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range seq {
+			for _, v := range s {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// FlatMap 对每个元素执行fn并展开结果
+func FlatMap[T, U any](slice []T, fn func(T) []U) []U {
+	return slices.Collect(FlatMapSeq(slices.Values(slice), func(v T) iter.Seq[U] {
+		return slices.Values(fn(v))
+	}))
+}
+
+// FlatMapSeq 对每个元素执行fn并惰性展开得到的序列
+func FlatMapSeq[T, U any](seq iter.Seq[T], fn func(T) iter.Seq[U]) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			for u := range fn(v) {
+				if !yield(u) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Find 返回第一个满足predicate的元素
+func Find[T any](slice []T, predicate func(T) bool) (T, bool) {
+	return FindSeq(slices.Values(slice), predicate)
+}
+
+// FindSeq 返回序列中第一个满足predicate的元素，找到后立即停止遍历
+func FindSeq[T any](seq iter.Seq[T], predicate func(T) bool) (T, bool) {
+	for v := range seq {
+		if predicate(v) {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Any 判断是否存在满足predicate的元素，空切片返回false
+func Any[T any](slice []T, predicate func(T) bool) bool {
+	return AnySeq(slices.Values(slice), predicate)
+}
+
+// AnySeq 判断序列中是否存在满足predicate的元素
+func AnySeq[T any](seq iter.Seq[T], predicate func(T) bool) bool {
+	_, found := FindSeq(seq, predicate)
+	return found
+}
+
+// All 判断是否所有元素都满足predicate，空切片返回true
+func All[T any](slice []T, predicate func(T) bool) bool {
+	return AllSeq(slices.Values(slice), predicate)
+}
+
+// AllSeq 判断序列中是否所有元素都满足predicate，遇到不满足的元素立即停止遍历
+func AllSeq[T any](seq iter.Seq[T], predicate func(T) bool) bool {
+	return !AnySeq(seq, func(v T) bool { return !predicate(v) })
+}
+
+// 32.1 集合操作辅助函数演示
+func demonstrateCollections() {
+	fmt.Println("\n=== 集合操作辅助函数演示 ===")
+
+	type Order struct {
+		ID     int
+		City   string
+		Amount float64
+		Tags   []string
+	}
+	orders := []Order{
+		{1, "北京", 120, []string{"生鲜", "加急"}},
+		{2, "上海", 80, []string{"日用"}},
+		{3, "北京", 45, []string{"日用", "生鲜"}},
+		{4, "深圳", 300, []string{"数码"}},
+		{5, "上海", 60, []string{"生鲜"}},
+	}
+
+	byCity := GroupBy(orders, func(o Order) string { return o.City })
+	for _, city := range []string{"北京", "上海", "深圳"} {
+		ids := Map(byCity[city], func(o Order) int { return o.ID })
+		fmt.Printf("%s的订单: %v\n", city, ids)
+	}
+	fmt.Printf("各城市订单数: %v\n", CountBy(orders, func(o Order) string { return o.City }))
+
+	large, small := Partition(orders, func(o Order) bool { return o.Amount >= 100 })
+	fmt.Printf("大额订单%d个, 小额订单%d个\n", len(large), len(small))
+
+	tags := FlatMap(orders, func(o Order) []string { return o.Tags })
+	fmt.Printf("所有标签: %v, 去重后: %v\n", tags, Distinct(tags))
+
+	if o, ok := Find(orders, func(o Order) bool { return o.City == "深圳" }); ok {
+		fmt.Printf("第一个深圳订单: #%d %.0f元\n", o.ID, o.Amount)
+	}
+	fmt.Printf("存在超过200元的订单: %v, 全部大于10元: %v\n",
+		Any(orders, func(o Order) bool { return o.Amount > 200 }),
+		All(orders, func(o Order) bool { return o.Amount > 10 }))
+
+	nums := []int{1, 2, 3, 4, 5, 6, 7}
+	fmt.Printf("Chunk(3): %v, Window(3): %v\n", Chunk(nums, 3), Window(nums, 3))
+	fmt.Printf("Flatten: %v\n", Flatten([][]int{{1, 2}, {}, {3}, {4, 5}}))
+
+	names := []string{"张三", "李四", "王五"}
+	scores := []int{88, 95, 72, 60}
+	pairs := Zip(names, scores)
+	fmt.Printf("Zip: %v\n", pairs)
+	n, s := Unzip(pairs)
+	fmt.Printf("Unzip: %v %v\n", n, s)
+
+	// Seq版本可以组合成惰性管道
+	words := strings.Fields("go is fun and go is fast and simple")
+	unique := DistinctSeq(slices.Values(words))
+	longWords := FilterSeq(unique, func(w string) bool { return len(w) > 2 })
+	fmt.Printf("去重后长度大于2的单词: %v\n", slices.Collect(longWords))
+
+	var moving []float64
+	for w := range WindowSeq(slices.Values([]float64{10, 20, 30, 40, 50}), 3) {
+		avg, _ := Mean(w)
+		moving = append(moving, avg)
+	}
+	fmt.Printf("3点移动平均: %v\n", moving)
+	fmt.Printf("单词长度分布: %v\n", CountBySeq(slices.Values(words), func(w string) int { return len(w) }))
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChunkAndWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []int
+		size    int
+		chunks  [][]int
+		windows [][]int
+	}{
+		{"空切片", nil, 2, [][]int{}, nil},
+		{"size大于长度", []int{1, 2}, 3, [][]int{{1, 2}}, nil},
+		{"size等于长度", []int{1, 2, 3}, 3, [][]int{{1, 2, 3}}, [][]int{{1, 2, 3}}},
+		{"size为1", []int{1, 2}, 1, [][]int{{1}, {2}}, [][]int{{1}, {2}}},
+		{"不能整除", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}, [][]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.input, tt.size); !reflect.DeepEqual(got, tt.chunks) {
+				t.Errorf("Chunk(%v, %d) = %v, want %v", tt.input, tt.size, got, tt.chunks)
+			}
+			if got := Window(tt.input, tt.size); !reflect.DeepEqual(got, tt.windows) {
+				t.Errorf("Window(%v, %d) = %v, want %v", tt.input, tt.size, got, tt.windows)
+			}
+			var lazy [][]int
+			for w := range WindowSeq(slices.Values(tt.input), tt.size) {
+				lazy = append(lazy, w)
+			}
+			if !reflect.DeepEqual(lazy, tt.windows) {
+				t.Errorf("WindowSeq(%v, %d) = %v, want %v", tt.input, tt.size, lazy, tt.windows)
+			}
+		})
+	}
+}
+
+func TestChunkAndWindowDoNotAlias(t *testing.T) {
+	// 返回的子切片容量被截断，append会重新分配而不会覆盖原切片
+	src := []int{1, 2, 3, 4, 5}
+	chunks := Chunk(src, 2)
+	for i := range chunks {
+		chunks[i] = append(chunks[i], 99)
+	}
+	windows := Window(src, 2)
+	for i := range windows {
+		windows[i] = append(windows[i], -1)
+	}
+	if !slices.Equal(src, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("appending to returned slices modified the source: %v", src)
+	}
+
+	// WindowSeq每次产生独立的切片，修改它不影响后续窗口
+	var got [][]int
+	for w := range WindowSeq(slices.Values(src), 3) {
+		got = append(got, w)
+		w[0] = 0
+	}
+	want := [][]int{{0, 2, 3}, {0, 3, 4}, {0, 4, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowSeq windows share storage: %v", got)
+	}
+}
+
+func TestChunkInvalidSize(t *testing.T) {
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"Chunk", func() { Chunk([]int{1}, 0) }},
+		{"Window", func() { Window([]int{1}, -1) }},
+		{"WindowSeq", func() { WindowSeq(slices.Values([]int{1}), 0) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s with size < 1 did not panic", tt.name)
+				}
+			}()
+			tt.call()
+		})
+	}
+}
+
+func TestGroupingHelpers(t *testing.T) {
+	words := []string{"go", "rust", "java", "c", "zig", "js"}
+	byLen := func(s string) int { return len(s) }
+	wantGroups := map[int][]string{2: {"go", "js"}, 4: {"rust", "java"}, 1: {"c"}, 3: {"zig"}}
+	if got := GroupBy(words, byLen); !reflect.DeepEqual(got, wantGroups) {
+		t.Errorf("GroupBy = %v, want %v", got, wantGroups)
+	}
+	if got := GroupBySeq(slices.Values(words), byLen); !reflect.DeepEqual(got, wantGroups) {
+		t.Errorf("GroupBySeq = %v, want %v", got, wantGroups)
+	}
+	wantCounts := map[int]int{2: 2, 4: 2, 1: 1, 3: 1}
+	if got := CountBy(words, byLen); !maps.Equal(got, wantCounts) {
+		t.Errorf("CountBy = %v, want %v", got, wantCounts)
+	}
+	if got := CountBySeq(slices.Values(words), byLen); !maps.Equal(got, wantCounts) {
+		t.Errorf("CountBySeq = %v, want %v", got, wantCounts)
+	}
+
+	short := func(s string) bool { return len(s) <= 2 }
+	matched, rest := Partition(words, short)
+	if !slices.Equal(matched, []string{"go", "c", "js"}) || !slices.Equal(rest, []string{"rust", "java", "zig"}) {
+		t.Errorf("Partition = %v, %v", matched, rest)
+	}
+	matched, rest = PartitionSeq(slices.Values(words), short)
+	if !slices.Equal(matched, []string{"go", "c", "js"}) || !slices.Equal(rest, []string{"rust", "java", "zig"}) {
+		t.Errorf("PartitionSeq = %v, %v", matched, rest)
+	}
+	if g := GroupBy([]string(nil), byLen); len(g) != 0 {
+		t.Errorf("GroupBy(nil) = %v", g)
+	}
+}
+
+func TestTransformHelpers(t *testing.T) {
+	pairs := Zip([]int{1, 2, 3}, []string{"a", "b"})
+	if want := []Pair[int, string]{{1, "a"}, {2, "b"}}; !slices.Equal(pairs, want) {
+		t.Errorf("Zip = %v, want %v", pairs, want)
+	}
+	keys, values := Unzip(pairs)
+	if !slices.Equal(keys, []int{1, 2}) || !slices.Equal(values, []string{"a", "b"}) {
+		t.Errorf("Unzip = %v, %v", keys, values)
+	}
+	keySeq, valueSeq := UnzipSeq(slices.Values(pairs))
+	if !slices.Equal(slices.Collect(keySeq), keys) || !slices.Equal(slices.Collect(valueSeq), values) {
+		t.Error("UnzipSeq differs from Unzip")
+	}
+	if got := Zip([]int(nil), []string{"a"}); len(got) != 0 {
+		t.Errorf("Zip with an empty slice = %v", got)
+	}
+
+	if got := Distinct([]int{3, 1, 3, 2, 1}); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("Distinct = %v", got)
+	}
+	if got := Flatten([][]int{{1}, nil, {2, 3}}); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Flatten = %v", got)
+	}
+	if got := FlatMap([]string{"a,b", "", "c"}, func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, ",")
+	}); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("FlatMap = %v", got)
+	}
+
+	even := func(v int) bool { return v%2 == 0 }
+	if v, ok := Find([]int{1, 3, 4, 6}, even); !ok || v != 4 {
+		t.Errorf("Find = %d, %v", v, ok)
+	}
+	if _, ok := Find([]int{1, 3}, even); ok {
+		t.Error("Find reported a match that does not exist")
+	}
+	// 空切片：Any为false，All为true
+	if Any([]int{}, even) || !All([]int{}, even) {
+		t.Error("Any/All on an empty slice")
+	}
+	if !Any([]int{1, 2}, even) || All([]int{1, 2}, even) || !All([]int{2, 4}, even) {
+		t.Error("Any/All results wrong")
+	}
+}
+
+func TestCollectionSeqEarlyExit(t *testing.T) {
+	// 调用方提前break时，每个Seq都必须停止拉取上游元素；适配器在yield返回false后继续yield会触发运行时panic
+	tests := []struct {
+		name string
+		seq  func(pulled *int) iter.Seq[int]
+		take int
+		want int // 产生take个元素时应当拉取的上游元素数
+	}{
+		{"WindowSeq", func(p *int) iter.Seq[int] {
+			return MapSeq(WindowSeq(countingSeq(100, p), 3), func(w []int) int { return w[0] })
+		}, 2, 4},
+		{"DistinctSeq", func(p *int) iter.Seq[int] {
+			return DistinctSeq(MapSeq(countingSeq(100, p), func(i int) int { return i / 3 }))
+		}, 2, 4},
+		{"FlattenSeq", func(p *int) iter.Seq[int] {
+			return FlattenSeq(MapSeq(countingSeq(100, p), func(i int) []int { return []int{i, i} }))
+		}, 3, 2},
+		{"FlatMapSeq", func(p *int) iter.Seq[int] {
+			return FlatMapSeq(countingSeq(100, p), func(i int) iter.Seq[int] { return slices.Values([]int{i, i, i}) })
+		}, 4, 2},
+		{"UnzipSeq键", func(p *int) iter.Seq[int] {
+			keys, _ := UnzipSeq(MapSeq(countingSeq(100, p), func(i int) Pair[int, int] { return Pair[int, int]{i, -i} }))
+			return keys
+		}, 3, 3},
+		{"UnzipSeq值", func(p *int) iter.Seq[int] {
+			_, values := UnzipSeq(MapSeq(countingSeq(100, p), func(i int) Pair[int, int] { return Pair[int, int]{i, -i} }))
+			return values
+		}, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pulled, taken := 0, 0
+			for range tt.seq(&pulled) {
+				if taken++; taken == tt.take {
+					break
+				}
+			}
+			if taken != tt.take || pulled != tt.want {
+				t.Errorf("took %d elements pulling %d, want %d pulling %d", taken, pulled, tt.take, tt.want)
+			}
+		})
+	}
+
+	// 消费型的函数在得到结果后立即停止
+	consumers := []struct {
+		name string
+		run  func(seq iter.Seq[int]) bool
+		want int
+	}{
+		{"FindSeq", func(seq iter.Seq[int]) bool { _, ok := FindSeq(seq, func(i int) bool { return i == 4 }); return ok }, 5},
+		{"AnySeq", func(seq iter.Seq[int]) bool { return AnySeq(seq, func(i int) bool { return i > 2 }) }, 4},
+		{"AllSeq", func(seq iter.Seq[int]) bool { return !AllSeq(seq, func(i int) bool { return i < 5 }) }, 6},
+	}
+	for _, c := range consumers {
+		t.Run(c.name, func(t *testing.T) {
+			pulled := 0
+			if !c.run(countingSeq(100, &pulled)) || pulled != c.want {
+				t.Errorf("pulled %d elements, want %d", pulled, c.want)
+			}
+		})
+	}
+}
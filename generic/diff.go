@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// 34. 序列差异比较
+// 使用Myers的O(ND)差分算法，N为两个序列的总长度，D为最少编辑次数
+// 采用线性空间的"中间蛇"分治版本，大文件也不需要保存每一步的搜索状态
+
+// EditOp 编辑操作类型
+type EditOp int
+
+const (
+	EditEqual  EditOp = iota // 两边相同的元素
+	EditDelete               // 只在a中出现，需要删除
+	EditInsert               // 只在b中出现，需要插入
+)
+
+// String 返回编辑操作的描述
+func (op EditOp) String() string {
+	switch op {
+	case EditEqual:
+		return "相同"
+	case EditDelete:
+		return "删除"
+	case EditInsert:
+		return "插入"
+	}
+	return "未知"
+}
+
+// Edit 编辑脚本中的一步，每一步对应一个元素
+// AIndex和BIndex是执行这一步之前在a和b中的位置：删除的是a[AIndex]，插入的是b[BIndex]
+type Edit[T any] struct {
+	Op     EditOp
+	AIndex int
+	BIndex int
+	Value  T
+}
+
+// Diff 计算把a变成b的最短编辑脚本
+func Diff[T comparable](a, b []T) []Edit[T] {
+	return DiffFunc(a, b, func(x, y T) bool { return x == y })
+}
+
+// DiffFunc 使用自定义的相等判断计算最短编辑脚本
+// 连续的修改中删除总是排在插入前面，Equal的Value取自a
+func DiffFunc[T any](a, b []T, eq func(x, y T) bool) []Edit[T] {
+	d := &differ[T]{a: a, b: b, eq: eq}
+	d.compare(0, len(a), 0, len(b))
+	edits := d.edits
+
+	// 把每一段连续修改中的删除移到插入前面，再统一计算下标
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditEqual {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != EditEqual {
+			j++
+		}
+		slices.SortStableFunc(edits[i:j], func(x, y Edit[T]) int { return int(x.Op) - int(y.Op) })
+		i = j
+	}
+	ai, bi := 0, 0
+	for i := range edits {
+		edits[i].AIndex, edits[i].BIndex = ai, bi
+		if edits[i].Op != EditInsert {
+			ai++
+		}
+		if edits[i].Op != EditDelete {
+			bi++
+		}
+	}
+	return edits
+}
+
+type differ[T any] struct {
+	a, b   []T
+	eq     func(x, y T) bool
+	edits  []Edit[T]
+	vf, vb []int // 中间蛇搜索时复用的数组
+}
+
+func (d *differ[T]) emit(op EditOp, v T) {
+	d.edits = append(d.edits, Edit[T]{Op: op, Value: v})
+}
+
+// compare 递归比较a[a0:a1]和b[b0:b1]，按顺序追加编辑步骤
+func (d *differ[T]) compare(a0, a1, b0, b1 int) {
+	for a0 < a1 && b0 < b1 && d.eq(d.a[a0], d.b[b0]) {
+		d.emit(EditEqual, d.a[a0])
+		a0++
+		b0++
+	}
+	suffix := 0
+	for a0 < a1 && b0 < b1 && d.eq(d.a[a1-1], d.b[b1-1]) {
+		a1--
+		b1--
+		suffix++
+	}
+
+	switch {
+	case a0 == a1:
+		for _, v := range d.b[b0:b1] {
+			d.emit(EditInsert, v)
+		}
+	case b0 == b1:
+		for _, v := range d.a[a0:a1] {
+			d.emit(EditDelete, v)
+		}
+	default:
+		// 去掉公共前后缀后至少需要两次编辑，所以两个子问题都严格变小
+		x, y, u, v := d.middleSnake(a0, a1, b0, b1)
+		d.compare(a0, x, b0, y)
+		for _, e := range d.a[x:u] {
+			d.emit(EditEqual, e)
+		}
+		d.compare(u, a1, v, b1)
+	}
+
+	for _, v := range d.a[a1 : a1+suffix] {
+		d.emit(EditEqual, v)
+	}
+}
+
+// middleSnake 同时从两端搜索，返回最短编辑路径中间那段对角线(x,y)->(u,v)
+// vf[k]是正向在对角线k=x-y上到达的最远x，vb[k]是反向在对角线k上距离终点的最远距离
+func (d *differ[T]) middleSnake(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	limit := (n + m + 1) / 2
+	off := limit + 1
+	if size := 2*limit + 3; cap(d.vf) < size {
+		d.vf, d.vb = make([]int, size), make([]int, size)
+	} else {
+		d.vf, d.vb = d.vf[:size], d.vb[:size]
+	}
+	vf, vb := d.vf, d.vb
+	vf[off+1], vb[off+1] = 0, 0
+
+	delta := n - m
+	odd := delta%2 != 0
+	for step := 0; step <= limit; step++ {
+		// 正向
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || (k != step && vf[off+k-1] < vf[off+k+1]) {
+				px = vf[off+k+1]
+			} else {
+				px = vf[off+k-1] + 1
+			}
+			py := px - k
+			sx, sy := px, py
+			for px < n && py < m && d.eq(d.a[a0+px], d.b[b0+py]) {
+				px++
+				py++
+			}
+			vf[off+k] = px
+			// 正向对角线k对应反向对角线delta-k，反向刚走完step-1步
+			if kr := delta - k; odd && kr >= -(step-1) && kr <= step-1 && px+vb[off+kr] >= n {
+				return a0 + sx, b0 + sy, a0 + px, b0 + py
+			}
+		}
+		// 反向：在倒序的序列上做同样的搜索
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || (k != step && vb[off+k-1] < vb[off+k+1]) {
+				px = vb[off+k+1]
+			} else {
+				px = vb[off+k-1] + 1
+			}
+			py := px - k
+			sx, sy := px, py
+			for px < n && py < m && d.eq(d.a[a1-1-px], d.b[b1-1-py]) {
+				px++
+				py++
+			}
+			vb[off+k] = px
+			if kf := delta - k; !odd && kf >= -step && kf <= step && px+vf[off+kf] >= n {
+				return a1 - px, b1 - py, a1 - sx, b1 - sy
+			}
+		}
+	}
+	panic("middleSnake: 未找到中间蛇")
+}
+
+// UnifiedDiff 以统一diff格式输出两组文本行的差异，context为每处修改前后保留的相同行数
+// 两组行完全相同时返回空字符串
+func UnifiedDiff(a, b []string, fromName, toName string, context int) string {
+	context = max(context, 0)
+	edits := Diff(a, b)
+	if !slices.ContainsFunc(edits, func(e Edit[string]) bool { return e.Op != EditEqual }) {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditEqual {
+			i++
+			continue
+		}
+		// 相邻两处修改之间的相同行不超过2*context时合并到同一个块
+		last := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].Op != EditEqual {
+				last = j
+			} else if j-last > 2*context {
+				break
+			}
+		}
+		start, stop := max(i-context, 0), min(last+context+1, len(edits))
+		hunk := edits[start:stop]
+
+		aCount, bCount := 0, 0
+		for _, e := range hunk {
+			if e.Op != EditInsert {
+				aCount++
+			}
+			if e.Op != EditDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunk[0].AIndex, aCount), hunkRange(hunk[0].BIndex, bCount))
+		for _, e := range hunk {
+			prefix := " "
+			switch e.Op {
+			case EditDelete:
+				prefix = "-"
+			case EditInsert:
+				prefix = "+"
+			}
+			sb.WriteString(prefix + e.Value + "\n")
+		}
+		i = stop
+	}
+	return sb.String()
+}
+
+// hunkRange 按GNU diff的习惯格式化块的行号范围：行号从1开始，只有一行时省略行数，
+// 没有行时起始行号取块前面那一行
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// 34.1 差异比较演示
+func demonstrateDiff() {
+	fmt.Println("\n=== 差异比较演示 ===")
+
+	for _, op := range Diff([]rune("kitten"), []rune("sitting")) {
+		fmt.Printf("%s%c ", map[EditOp]string{EditEqual: "", EditDelete: "-", EditInsert: "+"}[op.Op], op.Value)
+	}
+	fmt.Println()
+
+	// 比较同一个配置文件的两个版本
+	dir, err := os.MkdirTemp("", "diff-demo")
+	if err != nil {
+		fmt.Printf("创建临时目录失败: %v\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	v1 := "[server]\nhost = localhost\nport = 8080\ntimeout = 30\n\n[database]\ndriver = mysql\nhost = 127.0.0.1\nport = 3306\nuser = root\npool = 10\n\n[log]\nlevel = info\n"
+	v2 := "[server]\nhost = 0.0.0.0\nport = 8080\ntimeout = 30\n\n[database]\ndriver = mysql\nhost = 127.0.0.1\nport = 3306\nuser = app\npool = 10\n\n[log]\nlevel = debug\nfile = app.log\n"
+	oldPath, newPath := filepath.Join(dir, "config.v1.ini"), filepath.Join(dir, "config.v2.ini")
+	os.WriteFile(oldPath, []byte(v1), 0644)
+	os.WriteFile(newPath, []byte(v2), 0644)
+
+	readLines := func(path string) []string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+	fmt.Print(UnifiedDiff(readLines(oldPath), readLines(newPath), "config.v1.ini", "config.v2.ini", 1))
+
+	// 按ID比较记录，只关心哪些记录被增删
+	type Record struct {
+		ID    int
+		Name  string
+		Score int
+	}
+	before := []Record{{1, "张三", 80}, {2, "李四", 75}, {3, "王五", 90}, {4, "赵六", 60}}
+	after := []Record{{1, "张三", 85}, {3, "王五", 90}, {4, "赵六", 60}, {5, "钱七", 70}}
+	for _, e := range DiffFunc(before, after, func(x, y Record) bool { return x.ID == y.ID }) {
+		if e.Op != EditEqual {
+			fmt.Printf("%s记录: %+v\n", e.Op, e.Value)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// lcsLength 用动态规划计算最长公共子序列的长度，作为最短编辑脚本的参照
+func lcsLength[T comparable](a, b []T) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+// checkEditScript 检查编辑脚本的下标和值，并且按脚本能从a得到b，返回相同元素的个数
+func checkEditScript[T comparable](t *testing.T, a, b []T, edits []Edit[T]) int {
+	t.Helper()
+	ai, bi, equal := 0, 0, 0
+	for k, e := range edits {
+		if e.AIndex != ai || e.BIndex != bi {
+			t.Fatalf("edit %d at (%d, %d), want (%d, %d)", k, e.AIndex, e.BIndex, ai, bi)
+		}
+		switch e.Op {
+		case EditEqual:
+			if a[ai] != e.Value || b[bi] != e.Value {
+				t.Fatalf("edit %d: Equal %v, but a=%v b=%v", k, e.Value, a[ai], b[bi])
+			}
+			ai, bi, equal = ai+1, bi+1, equal+1
+		case EditDelete:
+			if a[ai] != e.Value {
+				t.Fatalf("edit %d: Delete %v, but a=%v", k, e.Value, a[ai])
+			}
+			// 连续修改中删除排在插入前面
+			if k > 0 && edits[k-1].Op == EditInsert {
+				t.Fatalf("edit %d: Delete after Insert", k)
+			}
+			ai++
+		case EditInsert:
+			if b[bi] != e.Value {
+				t.Fatalf("edit %d: Insert %v, but b=%v", k, e.Value, b[bi])
+			}
+			bi++
+		}
+	}
+	if ai != len(a) || bi != len(b) {
+		t.Fatalf("script consumed %d/%d of a and %d/%d of b", ai, len(a), bi, len(b))
+	}
+	return equal
+}
+
+func TestDiffMinimal(t *testing.T) {
+	// 字母表越小公共子序列越多，越容易暴露非最短的结果
+	r := rand.New(rand.NewSource(1))
+	gen := func() []byte {
+		s := make([]byte, r.Intn(25))
+		alphabet := 1 + r.Intn(3)
+		for i := range s {
+			s[i] = "abc"[r.Intn(alphabet)]
+		}
+		return s
+	}
+	for range 20000 {
+		a, b := gen(), gen()
+		equal := checkEditScript(t, a, b, Diff(a, b))
+		if want := lcsLength(a, b); equal != want {
+			t.Fatalf("Diff(%q, %q) keeps %d elements, LCS is %d", a, b, equal, want)
+		}
+	}
+}
+
+func TestDiffEdgeCases(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string // 每步用=、-、+加元素表示
+	}{
+		{"都为空", "", "", ""},
+		{"a为空", "", "xy", "+x+y"},
+		{"b为空", "xy", "", "-x-y"},
+		{"相同", "abc", "abc", "=a=b=c"},
+		{"完全不同", "ab", "cd", "-a-b+c+d"},
+		{"替换中间", "abc", "axc", "=a-b+x=c"},
+		{"前后插入", "b", "abc", "+a=b+c"},
+	}
+	symbols := map[EditOp]string{EditEqual: "=", EditDelete: "-", EditInsert: "+"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := []rune(tt.a), []rune(tt.b)
+			edits := Diff(a, b)
+			checkEditScript(t, a, b, edits)
+			var sb strings.Builder
+			for _, e := range edits {
+				sb.WriteString(symbols[e.Op] + string(e.Value))
+			}
+			if sb.String() != tt.want {
+				t.Errorf("Diff(%q, %q) = %s, want %s", tt.a, tt.b, sb.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	// 忽略大小写比较时，Equal的值取自a
+	a := []string{"Go", "RUST", "zig"}
+	b := []string{"go", "Rust", "c", "ZIG"}
+	edits := DiffFunc(a, b, strings.EqualFold)
+	var got []string
+	for _, e := range edits {
+		got = append(got, e.Op.String()+":"+e.Value)
+	}
+	want := "相同:Go 相同:RUST 插入:c 相同:zig"
+	if strings.Join(got, " ") != want {
+		t.Errorf("DiffFunc = %v, want %s", got, want)
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("跳过大输入测试")
+	}
+	// 线性空间的分治版本处理20万个元素时，耗时只取决于差异的多少
+	r := rand.New(rand.NewSource(2))
+	a := make([]int, 200000)
+	for i := range a {
+		a[i] = r.Int()
+	}
+	b := append([]int(nil), a...)
+	for range 500 {
+		b[r.Intn(len(b))] = -1
+	}
+	edits := Diff(a, b)
+	equal := checkEditScript(t, a, b, edits)
+	// 每个替换最多对应一次删除和一次插入
+	if changes := len(edits) - equal; changes > 1000 {
+		t.Errorf("%d changes for at most 500 substitutions", changes)
+	}
+}
+
+// applyUnifiedDiff 解析统一diff并应用到a，同时检查块头的行号、行数以及上下文行
+func applyUnifiedDiff(t *testing.T, a []string, patch string) []string {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(patch, "\n"), "\n")
+	if len(lines) < 3 || !strings.HasPrefix(lines[0], "--- ") || !strings.HasPrefix(lines[1], "+++ ") {
+		t.Fatalf("bad header:\n%s", patch)
+	}
+	parseRange := func(s string) (start, count int) {
+		startStr, countStr, found := strings.Cut(s, ",")
+		start, _ = strconv.Atoi(startStr)
+		count = 1
+		if found {
+			count, _ = strconv.Atoi(countStr)
+		}
+		return start, count
+	}
+
+	var out []string
+	pos := 0
+	for i := 2; i < len(lines); {
+		var aRange, bRange string
+		if _, err := fmt.Sscanf(lines[i], "@@ -%s +%s @@", &aRange, &bRange); err != nil {
+			t.Fatalf("bad hunk header %q", lines[i])
+		}
+		aStart, aCount := parseRange(aRange)
+		bStart, bCount := parseRange(bRange)
+		// 行数为0时起始行号指向块前面的一行
+		if aCount > 0 {
+			aStart--
+		}
+		if bCount > 0 {
+			bStart--
+		}
+		if aStart < pos {
+			t.Fatalf("hunk %q overlaps the previous one", lines[i])
+		}
+		out = append(out, a[pos:aStart]...)
+		if len(out) != bStart {
+			t.Fatalf("hunk %q starts at line %d of the result, want %d", lines[i], len(out), bStart)
+		}
+		pos = aStart
+		seenA, seenB := 0, 0
+		for i++; i < len(lines) && !strings.HasPrefix(lines[i], "@@"); i++ {
+			op, text := lines[i][0], lines[i][1:]
+			if op != '+' {
+				if pos >= len(a) || a[pos] != text {
+					t.Fatalf("line %q does not match a[%d]", lines[i], pos)
+				}
+				pos++
+				seenA++
+			}
+			if op != '-' {
+				out = append(out, text)
+				seenB++
+			}
+		}
+		if seenA != aCount || seenB != bCount {
+			t.Fatalf("hunk has %d/%d lines, header says %d/%d", seenA, seenB, aCount, bCount)
+		}
+	}
+	return append(out, a[pos:]...)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := strings.Split("a b c d e f g", " ")
+	b := strings.Split("a c d e f G g h", " ")
+	want := `--- old
++++ new
+@@ -1,3 +1,2 @@
+ a
+-b
+ c
+@@ -6,2 +5,4 @@
+ f
++G
+ g
++h
+`
+	if got := UnifiedDiff(a, b, "old", "new", 1); got != want {
+		t.Errorf("UnifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+	// 上下文足够大时两处修改合并为一个块
+	if got := UnifiedDiff(a, b, "old", "new", 2); strings.Count(got, "@@ ") != 1 {
+		t.Errorf("context 2 produced:\n%s", got)
+	}
+	if got := UnifiedDiff([]string{"x"}, []string{"x", "y"}, "a", "b", 0); !strings.Contains(got, "@@ -1,0 +2 @@\n+y\n") {
+		t.Errorf("insertion without context:\n%s", got)
+	}
+	if got := UnifiedDiff(a, a, "old", "new", 3); got != "" {
+		t.Errorf("identical input produced:\n%s", got)
+	}
+}
+
+func TestUnifiedDiffApplies(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	gen := func() []string {
+		s := make([]string, r.Intn(30))
+		for i := range s {
+			s[i] = string("abcd"[r.Intn(4)])
+		}
+		return s
+	}
+	for range 2000 {
+		a, b := gen(), gen()
+		context := r.Intn(4)
+		patch := UnifiedDiff(a, b, "a", "b", context)
+		if patch == "" {
+			if strings.Join(a, "\n") != strings.Join(b, "\n") {
+				t.Fatalf("empty diff for different input %q, %q", a, b)
+			}
+			continue
+		}
+		if got := applyUnifiedDiff(t, a, patch); strings.Join(got, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("applying the diff gave %q, want %q\n%s", got, b, patch)
+		}
+	}
+}
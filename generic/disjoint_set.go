@@ -0,0 +1,255 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// 33. 并查集
+// 元素映射为连续的下标，父节点、秩和集合大小都保存在切片中，百万级元素也只需要几个连续数组
+// Find使用路径压缩，Union按秩合并，单次操作的均摊复杂度接近常数
+
+// DisjointSet 泛型并查集，零值可以直接使用
+type DisjointSet[T comparable] struct {
+	index  map[T]int32
+	elems  []T     // 下标到元素
+	parent []int32 // 父节点下标，根节点指向自己
+	rank   []uint8 // 按秩合并时秩不超过log2(n)
+	size   []int32 // 只有根节点上的值有效
+	count  int     // 集合个数
+}
+
+// NewDisjointSet 创建并查集，capacity为预计的元素个数，用于预分配空间
+func NewDisjointSet[T comparable](capacity int) *DisjointSet[T] {
+	if capacity < 0 {
+		panic("NewDisjointSet: 容量不能为负数")
+	}
+	return &DisjointSet[T]{
+		index:  make(map[T]int32, capacity),
+		elems:  make([]T, 0, capacity),
+		parent: make([]int32, 0, capacity),
+		rank:   make([]uint8, 0, capacity),
+		size:   make([]int32, 0, capacity),
+	}
+}
+
+// Len 返回元素个数
+func (ds *DisjointSet[T]) Len() int {
+	return len(ds.elems)
+}
+
+// Count 返回集合个数
+func (ds *DisjointSet[T]) Count() int {
+	return ds.count
+}
+
+// Contains 判断元素是否存在
+func (ds *DisjointSet[T]) Contains(v T) bool {
+	_, ok := ds.index[v]
+	return ok
+}
+
+// MakeSet 创建只包含v的集合，v已存在时返回false
+func (ds *DisjointSet[T]) MakeSet(v T) bool {
+	if ds.Contains(v) {
+		return false
+	}
+	ds.add(v)
+	return true
+}
+
+// add 加入新元素作为单独的集合，返回它的下标
+func (ds *DisjointSet[T]) add(v T) int32 {
+	if ds.index == nil {
+		ds.index = make(map[T]int32)
+	}
+	i := int32(len(ds.elems))
+	ds.index[v] = i
+	ds.elems = append(ds.elems, v)
+	ds.parent = append(ds.parent, i)
+	ds.rank = append(ds.rank, 0)
+	ds.size = append(ds.size, 1)
+	ds.count++
+	return i
+}
+
+// indexOf 返回v的下标，不存在时自动创建
+func (ds *DisjointSet[T]) indexOf(v T) int32 {
+	if i, ok := ds.index[v]; ok {
+		return i
+	}
+	return ds.add(v)
+}
+
+// root 返回下标i所在集合的根，并把路径上的节点直接挂到根上
+// 使用两趟循环而不是递归，避免长链导致栈过深
+func (ds *DisjointSet[T]) root(i int32) int32 {
+	r := i
+	for ds.parent[r] != r {
+		r = ds.parent[r]
+	}
+	for ds.parent[i] != r {
+		ds.parent[i], i = r, ds.parent[i]
+	}
+	return r
+}
+
+// Find 返回v所在集合的代表元素，v不存在时返回false
+func (ds *DisjointSet[T]) Find(v T) (T, bool) {
+	i, ok := ds.index[v]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return ds.elems[ds.root(i)], true
+}
+
+// Union 合并a和b所在的集合，不存在的元素会自动创建
+// 两者原本就在同一集合时返回false
+func (ds *DisjointSet[T]) Union(a, b T) bool {
+	ra, rb := ds.root(ds.indexOf(a)), ds.root(ds.indexOf(b))
+	if ra == rb {
+		return false
+	}
+	// 秩小的树挂到秩大的树下，树高保持在O(log n)
+	if ds.rank[ra] < ds.rank[rb] {
+		ra, rb = rb, ra
+	}
+	ds.parent[rb] = ra
+	ds.size[ra] += ds.size[rb]
+	if ds.rank[ra] == ds.rank[rb] {
+		ds.rank[ra]++
+	}
+	ds.count--
+	return true
+}
+
+// Connected 判断a和b是否在同一集合，任一元素不存在时返回false
+func (ds *DisjointSet[T]) Connected(a, b T) bool {
+	i, ok := ds.index[a]
+	if !ok {
+		return false
+	}
+	j, ok := ds.index[b]
+	if !ok {
+		return false
+	}
+	return ds.root(i) == ds.root(j)
+}
+
+// SizeOf 返回v所在集合的元素个数，v不存在时返回0
+func (ds *DisjointSet[T]) SizeOf(v T) int {
+	i, ok := ds.index[v]
+	if !ok {
+		return 0
+	}
+	return int(ds.size[ds.root(i)])
+}
+
+// Components 返回所有集合
+// 集合按其中最早加入的元素排序，集合内的元素也按加入顺序排列
+func (ds *DisjointSet[T]) Components() [][]T {
+	slot := make(map[int32]int, ds.count) // 根下标到结果下标
+	components := make([][]T, 0, ds.count)
+	for i, v := range ds.elems {
+		r := ds.root(int32(i))
+		k, ok := slot[r]
+		if !ok {
+			k = len(components)
+			slot[r] = k
+			components = append(components, make([]T, 0, ds.size[r]))
+		}
+		components[k] = append(components[k], v)
+	}
+	return components
+}
+
+// KruskalMST 使用Kruskal算法和并查集求无向图的最小生成树（森林）
+// 结果与Prim算法的MinimumSpanningTree总权重相同，适合稀疏图
+func KruskalMST[K comparable, W Weight](g *Graph[K, W]) ([]Edge[K, W], W, error) {
+	var total W
+	if g.directed {
+		return nil, total, ErrNotUndirected
+	}
+
+	edges := g.Edges()
+	slices.SortStableFunc(edges, func(a, b Edge[K, W]) int { return cmp.Compare(a.Weight, b.Weight) })
+
+	ds := NewDisjointSet[K](len(g.vertices))
+	for _, v := range g.vertices {
+		ds.MakeSet(v)
+	}
+	var tree []Edge[K, W]
+	for _, e := range edges {
+		if ds.Union(e.From, e.To) {
+			tree = append(tree, e)
+			total += e.Weight
+			if len(tree) == len(g.vertices)-1 {
+				break
+			}
+		}
+	}
+	return tree, total, nil
+}
+
+// 33.1 并查集演示
+func demonstrateDisjointSet() {
+	fmt.Println("\n=== 并查集演示 ===")
+
+	// 社交网络中的朋友圈
+	friends := NewDisjointSet[string](8)
+	for _, p := range [][2]string{
+		{"张三", "李四"}, {"李四", "王五"}, {"赵六", "钱七"}, {"孙八", "孙八"},
+	} {
+		friends.Union(p[0], p[1])
+	}
+	friends.MakeSet("周九")
+	fmt.Printf("%d 人, %d 个朋友圈: %v\n", friends.Len(), friends.Count(), friends.Components())
+	fmt.Printf("张三和王五是否在同一朋友圈: %v, 张三和赵六: %v\n",
+		friends.Connected("张三", "王五"), friends.Connected("张三", "赵六"))
+	if rep, ok := friends.Find("王五"); ok {
+		fmt.Printf("王五所在朋友圈的代表: %s, 人数: %d\n", rep, friends.SizeOf("王五"))
+	}
+
+	// 与Prim算法对比最小生成树
+	roads := NewGraph[string, int](false)
+	roads.AddEdge("北京", "天津", 137)
+	roads.AddEdge("北京", "石家庄", 283)
+	roads.AddEdge("天津", "济南", 360)
+	roads.AddEdge("石家庄", "郑州", 412)
+	roads.AddEdge("济南", "郑州", 470)
+	roads.AddEdge("济南", "南京", 617)
+	roads.AddEdge("郑州", "南京", 695)
+	_, primTotal, _ := MinimumSpanningTree(roads)
+	kruskal, kruskalTotal, _ := KruskalMST(roads)
+	fmt.Printf("Kruskal最小生成树: %d 条边, 总长度 %d 公里 (Prim: %d)\n", len(kruskal), kruskalTotal, primTotal)
+
+	// 一维坐标聚类：相邻点距离不超过阈值的归为一类
+	points := []float64{1.0, 1.4, 2.1, 5.0, 5.3, 9.8, 10.1, 10.5}
+	clusters := NewDisjointSet[float64](len(points))
+	for i, p := range points {
+		clusters.MakeSet(p)
+		if i > 0 && p-points[i-1] <= 0.8 {
+			clusters.Union(points[i-1], p)
+		}
+	}
+	fmt.Printf("阈值0.8的聚类结果: %v\n", clusters.Components())
+
+	// 百万级元素
+	const n = 1_000_000
+	start := time.Now()
+	big := NewDisjointSet[int](n)
+	for i := range n {
+		big.MakeSet(i)
+	}
+	for i := 0; i+1 < n; i += 2 {
+		big.Union(i, i+1)
+	}
+	for i := 0; i+2 < n; i += 4 {
+		big.Union(i, i+2)
+	}
+	fmt.Printf("%d 个元素合并后剩 %d 个集合, 0和3连通: %v, 耗时 %v\n",
+		big.Len(), big.Count(), big.Connected(0, 3), time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"math/bits"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDisjointSetRandomOps(t *testing.T) {
+	// 参照实现：每个元素记录所在集合的标签，合并时改写整个集合的标签
+	r := rand.New(rand.NewSource(1))
+	var ds DisjointSet[int]
+	label := map[int]int{}
+	for i := 0; i < 20000; i++ {
+		a, b := r.Intn(500), r.Intn(500)
+		for _, x := range []int{a, b} {
+			if _, ok := label[x]; !ok {
+				label[x] = x
+			}
+		}
+		la, lb := label[a], label[b]
+		if ds.Union(a, b) != (la != lb) {
+			t.Fatalf("Union(%d, %d) = %v, want %v", a, b, la == lb, la != lb)
+		}
+		if la != lb {
+			for k, v := range label {
+				if v == lb {
+					label[k] = la
+				}
+			}
+		}
+
+		// 查询包括从未加入的元素
+		c, d := r.Intn(600), r.Intn(600)
+		lc, okc := label[c]
+		ld, okd := label[d]
+		if got := ds.Connected(c, d); got != (okc && okd && lc == ld) {
+			t.Fatalf("Connected(%d, %d) = %v", c, d, got)
+		}
+		size := 0
+		for _, v := range label {
+			if okc && v == lc {
+				size++
+			}
+		}
+		if got := ds.SizeOf(c); got != size {
+			t.Fatalf("SizeOf(%d) = %d, want %d", c, got, size)
+		}
+		if rep, ok := ds.Find(c); ok != okc || ok && label[rep] != lc {
+			t.Fatalf("Find(%d) = %d, %v", c, rep, ok)
+		}
+	}
+
+	if ds.Len() != len(label) {
+		t.Errorf("Len() = %d, want %d", ds.Len(), len(label))
+	}
+	labels := map[int]bool{}
+	for _, v := range label {
+		labels[v] = true
+	}
+	components := ds.Components()
+	if len(components) != ds.Count() || len(components) != len(labels) {
+		t.Fatalf("%d components, Count() = %d, want %d", len(components), ds.Count(), len(labels))
+	}
+	total := 0
+	for _, c := range components {
+		total += len(c)
+		for _, x := range c {
+			if label[x] != label[c[0]] {
+				t.Fatalf("component %v mixes sets", c)
+			}
+		}
+	}
+	if total != len(label) {
+		t.Errorf("components hold %d elements, want %d", total, len(label))
+	}
+	// 按秩合并保证秩不超过log2(n)
+	for i, rank := range ds.rank {
+		if int(rank) > bits.Len(uint(ds.Len())) {
+			t.Fatalf("element %v has rank %d with %d elements", ds.elems[i], rank, ds.Len())
+		}
+	}
+}
+
+func TestDisjointSetBasics(t *testing.T) {
+	var ds DisjointSet[string]
+	if _, ok := ds.Find("a"); ok || ds.Connected("a", "a") || ds.SizeOf("a") != 0 {
+		t.Fatal("zero value reports elements")
+	}
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		if !ds.MakeSet(v) {
+			t.Fatalf("MakeSet(%q) = false", v)
+		}
+	}
+	if ds.MakeSet("a") {
+		t.Error("MakeSet of an existing element returned true")
+	}
+	ds.Union("d", "b")
+	ds.Union("e", "a")
+	if ds.Union("a", "e") {
+		t.Error("Union within one set returned true")
+	}
+	if !ds.Connected("e", "a") || ds.Connected("a", "b") || !ds.Connected("c", "c") {
+		t.Error("Connected results wrong")
+	}
+	// 集合按最早加入的元素排序，集合内按加入顺序排列
+	want := [][]string{{"a", "e"}, {"b", "d"}, {"c"}}
+	got := ds.Components()
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Components() = %v, want %v", got, want)
+	}
+	if ds.Count() != 3 || ds.Len() != 5 {
+		t.Errorf("Count() = %d, Len() = %d", ds.Count(), ds.Len())
+	}
+	// Union会自动创建不存在的元素
+	if !ds.Union("f", "c") || ds.SizeOf("c") != 2 || ds.Len() != 6 {
+		t.Error("Union with a new element")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewDisjointSet(-1) did not panic")
+		}
+	}()
+	NewDisjointSet[int](-1)
+}
+
+func TestKruskalMST(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		// 随机图可能不连通，包含负权边、平行边和自环
+		g := NewGraph[int, int](false)
+		for v := range 20 {
+			g.AddVertex(v)
+		}
+		for range r.Intn(40) {
+			g.AddEdge(r.Intn(20), r.Intn(20), r.Intn(10)-2)
+		}
+		tree, total, err := KruskalMST(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, primTotal, _ := MinimumSpanningTree(g)
+		if total != primTotal {
+			t.Fatalf("KruskalMST total %d, MinimumSpanningTree total %d", total, primTotal)
+		}
+
+		// 结果是生成森林：没有环，边数等于顶点数减去连通分量数
+		forest := NewDisjointSet[int](20)
+		components := NewDisjointSet[int](20)
+		sum := 0
+		for v := range 20 {
+			components.MakeSet(v)
+		}
+		for _, e := range g.Edges() {
+			components.Union(e.From, e.To)
+		}
+		for _, e := range tree {
+			if !forest.Union(e.From, e.To) {
+				t.Fatalf("tree edge %v closes a cycle", e)
+			}
+			sum += e.Weight
+		}
+		if sum != total {
+			t.Errorf("tree edges sum to %d, reported total %d", sum, total)
+		}
+		if len(tree) != 20-components.Count() {
+			t.Errorf("tree has %d edges, want %d", len(tree), 20-components.Count())
+		}
+	}
+
+	if _, _, err := KruskalMST(NewGraph[int, int](true)); !errors.Is(err, ErrNotUndirected) {
+		t.Errorf("KruskalMST on a directed graph = %v", err)
+	}
+}
@@ -0,0 +1,959 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// 30. 容器的JSON与二进制编码
+// 容器的字段都是未导出的，encoding/json默认只能得到{}，这里为每个容器实现
+// json.Marshaler/Unmarshaler 和 encoding.BinaryMarshaler/BinaryUnmarshaler
+// JSON使用最自然的形式：线性容器编码为数组，有序映射编码为键值对数组（保留顺序，且支持任意Ordered键）
+// 二进制编码使用gob编码同样的数据，元素类型需要能被gob编码
+// 布隆过滤器和Count-Min Sketch没有可以列出的元素，JSON中保存为它们二进制格式的base64字符串
+// Marshal方法尽量使用值接收者，与Set相同，结构体中的容器字段无论是否可寻址都能正确编码
+// SkipList和Cache包含锁，不能按值复制，只能使用指针接收者：作为结构体字段时请保存指针，
+// 或者编码结构体的指针（json.Marshal(&v)）；按值编码结构体时这两个字段会被静默编码为{}
+
+// ErrNoComparator 反序列化到没有比较函数的优先队列时返回的错误
+var ErrNoComparator = errors.New("优先队列未设置比较函数，请先用NewPriorityQueue或NewPriorityQueueFunc创建")
+
+// gobMarshal 用gob编码v
+func gobMarshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gobUnmarshal 用gob将data解码到v
+func gobUnmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// MarshalJSON 从栈底到栈顶编码为JSON数组，空栈编码为[]而不是null
+func (s Stack[T]) MarshalJSON() ([]byte, error) {
+	if s.elements == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.elements)
+}
+
+// UnmarshalJSON 从JSON数组恢复栈，最后一个元素为栈顶，null不做任何修改
+func (s *Stack[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, &s.elements)
+}
+
+// MarshalBinary 从栈底到栈顶编码为二进制
+func (s Stack[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(s.elements)
+}
+
+// UnmarshalBinary 从二进制数据恢复栈
+func (s *Stack[T]) UnmarshalBinary(data []byte) error {
+	s.elements = nil
+	return gobUnmarshal(data, &s.elements)
+}
+
+// MarshalJSON 从头到尾编码为JSON数组
+func (l LinkedList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.ToSlice())
+}
+
+// UnmarshalJSON 从JSON数组恢复链表，原有元素会被清空，null不做任何修改
+func (l *LinkedList[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	l.setValues(values)
+	return nil
+}
+
+// MarshalBinary 从头到尾编码为二进制
+func (l LinkedList[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(l.ToSlice())
+}
+
+// UnmarshalBinary 从二进制数据恢复链表，原有元素会被清空
+func (l *LinkedList[T]) UnmarshalBinary(data []byte) error {
+	var values []T
+	if err := gobUnmarshal(data, &values); err != nil {
+		return err
+	}
+	l.setValues(values)
+	return nil
+}
+
+// setValues 用values替换链表的全部元素
+func (l *LinkedList[T]) setValues(values []T) {
+	l.Clear()
+	for _, v := range values {
+		l.PushBack(v)
+	}
+}
+
+// pairs 按键的顺序返回所有键值对
+func (m *TreeMap[K, V]) pairs() []Pair[K, V] {
+	result := make([]Pair[K, V], 0, m.size)
+	for p := range m.All() {
+		result = append(result, p)
+	}
+	return result
+}
+
+// setPairs 用pairs替换映射的全部内容，重复的键以后出现的为准
+func (m *TreeMap[K, V]) setPairs(pairs []Pair[K, V]) {
+	m.root, m.size = nil, 0
+	for _, p := range pairs {
+		m.Put(p.Key, p.Value)
+	}
+}
+
+// MarshalJSON 按键的顺序编码为键值对数组
+func (m TreeMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.pairs())
+}
+
+// UnmarshalJSON 从键值对数组恢复映射，null不做任何修改
+func (m *TreeMap[K, V]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var pairs []Pair[K, V]
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+	m.setPairs(pairs)
+	return nil
+}
+
+// MarshalBinary 按键的顺序编码为二进制
+func (m TreeMap[K, V]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(m.pairs())
+}
+
+// UnmarshalBinary 从二进制数据恢复映射
+func (m *TreeMap[K, V]) UnmarshalBinary(data []byte) error {
+	var pairs []Pair[K, V]
+	if err := gobUnmarshal(data, &pairs); err != nil {
+		return err
+	}
+	m.setPairs(pairs)
+	return nil
+}
+
+// MarshalBinary 将集合编码为二进制
+func (s Set[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(s.ToSlice())
+}
+
+// UnmarshalBinary 从二进制数据恢复集合
+func (s *Set[T]) UnmarshalBinary(data []byte) error {
+	var values []T
+	if err := gobUnmarshal(data, &values); err != nil {
+		return err
+	}
+	s.items = make(map[T]struct{}, len(values))
+	s.Add(values...)
+	return nil
+}
+
+// values 按堆中的顺序返回所有元素
+func (pq *PriorityQueue[T]) values() []T {
+	result := make([]T, len(pq.items))
+	for i, item := range pq.items {
+		result[i] = item.value
+	}
+	return result
+}
+
+// setValues 用values替换队列的全部元素并重新建堆，原有的句柄全部失效
+func (pq *PriorityQueue[T]) setValues(values []T) error {
+	if pq.less == nil {
+		return ErrNoComparator
+	}
+	for _, item := range pq.items {
+		item.index = -1
+	}
+	pq.items = make([]*PQItem[T], len(values))
+	for i, v := range values {
+		pq.items[i] = &PQItem[T]{value: v, index: i}
+	}
+	for i := len(pq.items)/2 - 1; i >= 0; i-- {
+		pq.down(i)
+	}
+	return nil
+}
+
+// MarshalJSON 编码为JSON数组，元素按堆中的顺序排列而不是出队顺序
+func (pq PriorityQueue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pq.values())
+}
+
+// UnmarshalJSON 从JSON数组恢复队列，接收者必须已经设置了比较函数
+// 按encoding/json的约定，null不做任何修改
+func (pq *PriorityQueue[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	return pq.setValues(values)
+}
+
+// MarshalBinary 编码为二进制
+func (pq PriorityQueue[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(pq.values())
+}
+
+// UnmarshalBinary 从二进制数据恢复队列，接收者必须已经设置了比较函数
+func (pq *PriorityQueue[T]) UnmarshalBinary(data []byte) error {
+	var values []T
+	if err := gobUnmarshal(data, &values); err != nil {
+		return err
+	}
+	return pq.setValues(values)
+}
+
+// ringData 环形缓冲区的编码形式
+type ringData[T any] struct {
+	Capacity int            `json:"capacity"`
+	Policy   OverflowPolicy `json:"policy"`
+	Values   []T            `json:"values"`
+}
+
+// data 返回环形缓冲区的编码形式
+func (r *Ring[T]) data() ringData[T] {
+	return ringData[T]{Capacity: len(r.buf), Policy: r.policy, Values: r.ToSlice()}
+}
+
+// setData 从编码形式恢复环形缓冲区，丢弃计数归零
+// 容量为0是零值环形缓冲区的编码，恢复为同样不能写入的零值
+func (r *Ring[T]) setData(d ringData[T]) error {
+	if d.Capacity < 0 || len(d.Values) > d.Capacity {
+		return fmt.Errorf("环形缓冲区容量%d, 元素%d个: %w", d.Capacity, len(d.Values), ErrInvalidEncoding)
+	}
+	*r = Ring[T]{buf: make([]T, d.Capacity), policy: d.Policy, size: len(d.Values)}
+	copy(r.buf, d.Values)
+	return nil
+}
+
+// MarshalJSON 编码为包含容量、策略和元素（从旧到新）的JSON对象
+func (r Ring[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.data())
+}
+
+// UnmarshalJSON 从JSON对象恢复环形缓冲区，null不做任何修改
+func (r *Ring[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var d ringData[T]
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	return r.setData(d)
+}
+
+// MarshalBinary 编码为二进制
+func (r Ring[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(r.data())
+}
+
+// UnmarshalBinary 从二进制数据恢复环形缓冲区
+func (r *Ring[T]) UnmarshalBinary(data []byte) error {
+	var d ringData[T]
+	if err := gobUnmarshal(data, &d); err != nil {
+		return err
+	}
+	return r.setData(d)
+}
+
+// setValues 用values替换队列的全部元素，保留预分配的容量
+func (d *Deque[T]) setValues(values []T) {
+	c := max(minDequeCap, d.minCap)
+	for c < len(values) {
+		c <<= 1
+	}
+	*d = Deque[T]{buf: make([]T, c), size: len(values), minCap: d.minCap}
+	copy(d.buf, values)
+}
+
+// MarshalJSON 从头到尾编码为JSON数组
+func (d Deque[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.ToSlice())
+}
+
+// UnmarshalJSON 从JSON数组恢复双端队列，null不做任何修改
+func (d *Deque[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	d.setValues(values)
+	return nil
+}
+
+// MarshalBinary 从头到尾编码为二进制
+func (d Deque[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(d.ToSlice())
+}
+
+// UnmarshalBinary 从二进制数据恢复双端队列
+func (d *Deque[T]) UnmarshalBinary(data []byte) error {
+	var values []T
+	if err := gobUnmarshal(data, &values); err != nil {
+		return err
+	}
+	d.setValues(values)
+	return nil
+}
+
+// pairs 按键的顺序返回所有键值对
+func (s *SkipList[K, V]) pairs() []Pair[K, V] {
+	if s.head == nil {
+		return []Pair[K, V]{}
+	}
+	result := make([]Pair[K, V], 0, s.Size())
+	for p := range s.All() {
+		result = append(result, p)
+	}
+	return result
+}
+
+// setPairs 用pairs替换跳表的全部内容，重复的键以后出现的为准
+// 零值跳表（例如结构体中的字段）会先创建头节点；正在遍历的读者仍然能看到旧节点
+func (s *SkipList[K, V]) setPairs(pairs []Pair[K, V]) {
+	s.mu.Lock()
+	if s.head == nil {
+		s.initHead()
+	} else {
+		for i := range s.head.next {
+			s.head.next[i].Store(nil)
+			s.head.span[i] = 0
+		}
+		s.level.Store(1)
+		s.size.Store(0)
+	}
+	s.mu.Unlock()
+	for _, p := range pairs {
+		s.Put(p.Key, p.Value)
+	}
+}
+
+// MarshalJSON 按键的顺序编码为键值对数组
+// SkipList包含互斥锁，不能按值复制，因此使用指针接收者
+// 按值保存在结构体中时需要编码结构体的指针，否则encoding/json调用不到这个方法
+func (s *SkipList[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.pairs())
+}
+
+// UnmarshalJSON 从键值对数组恢复跳表，原有内容会被清空，null不做任何修改
+func (s *SkipList[K, V]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var pairs []Pair[K, V]
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+	s.setPairs(pairs)
+	return nil
+}
+
+// MarshalBinary 按键的顺序编码为二进制
+func (s *SkipList[K, V]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(s.pairs())
+}
+
+// UnmarshalBinary 从二进制数据恢复跳表，原有内容会被清空
+func (s *SkipList[K, V]) UnmarshalBinary(data []byte) error {
+	var pairs []Pair[K, V]
+	if err := gobUnmarshal(data, &pairs); err != nil {
+		return err
+	}
+	s.setPairs(pairs)
+	return nil
+}
+
+// toMap 将所有键值复制到map
+func (r *RadixTree[V]) toMap() map[string]V {
+	result := make(map[string]V, r.Len())
+	for k, v := range r.All() {
+		result[k] = v
+	}
+	return result
+}
+
+// setMap 用m替换基数树的全部内容
+func (r *RadixTree[V]) setMap(m map[string]V) {
+	*r = *NewRadixTree[V]()
+	for k, v := range m {
+		r.Insert(k, v)
+	}
+}
+
+// MarshalJSON 编码为JSON对象
+func (r RadixTree[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.toMap())
+}
+
+// UnmarshalJSON 从JSON对象恢复基数树，null不做任何修改
+func (r *RadixTree[V]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var m map[string]V
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	r.setMap(m)
+	return nil
+}
+
+// MarshalBinary 编码为二进制
+func (r RadixTree[V]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(r.toMap())
+}
+
+// UnmarshalBinary 从二进制数据恢复基数树
+func (r *RadixTree[V]) UnmarshalBinary(data []byte) error {
+	var m map[string]V
+	if err := gobUnmarshal(data, &m); err != nil {
+		return err
+	}
+	r.setMap(m)
+	return nil
+}
+
+// pairs 按字节序返回所有键值对
+func (r *ByteRadixTree[V]) pairs() []Pair[[]byte, V] {
+	result := make([]Pair[[]byte, V], 0, r.Len())
+	for k, v := range r.WalkPrefix(nil) {
+		result = append(result, Pair[[]byte, V]{Key: k, Value: v})
+	}
+	return result
+}
+
+// setPairs 用pairs替换基数树的全部内容
+func (r *ByteRadixTree[V]) setPairs(pairs []Pair[[]byte, V]) {
+	*r = *NewByteRadixTree[V]()
+	for _, p := range pairs {
+		r.Insert(p.Key, p.Value)
+	}
+}
+
+// MarshalJSON 编码为键值对数组，键按encoding/json的规则编码为base64字符串
+func (r ByteRadixTree[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.pairs())
+}
+
+// UnmarshalJSON 从键值对数组恢复基数树，null不做任何修改
+func (r *ByteRadixTree[V]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var pairs []Pair[[]byte, V]
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+	r.setPairs(pairs)
+	return nil
+}
+
+// MarshalBinary 编码为二进制
+func (r ByteRadixTree[V]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(r.pairs())
+}
+
+// UnmarshalBinary 从二进制数据恢复基数树
+func (r *ByteRadixTree[V]) UnmarshalBinary(data []byte) error {
+	var pairs []Pair[[]byte, V]
+	if err := gobUnmarshal(data, &pairs); err != nil {
+		return err
+	}
+	r.setPairs(pairs)
+	return nil
+}
+
+// rowSlices 按行返回矩阵的元素
+func (m *Matrix[T]) rowSlices() [][]T {
+	rows := make([][]T, m.rows)
+	for i := range rows {
+		rows[i] = m.Row(i)
+	}
+	return rows
+}
+
+// setRows 从二维切片恢复矩阵
+func (m *Matrix[T]) setRows(rows [][]T) error {
+	loaded, err := MatrixFromRows(rows)
+	if err != nil {
+		return err
+	}
+	*m = *loaded
+	return nil
+}
+
+// MarshalJSON 编码为二维JSON数组
+func (m Matrix[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.rowSlices())
+}
+
+// UnmarshalJSON 从二维JSON数组恢复矩阵，每一行的长度必须相同，null不做任何修改
+func (m *Matrix[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var rows [][]T
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+	return m.setRows(rows)
+}
+
+// MarshalBinary 编码为二进制
+func (m Matrix[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(m.rowSlices())
+}
+
+// UnmarshalBinary 从二进制数据恢复矩阵
+func (m *Matrix[T]) UnmarshalBinary(data []byte) error {
+	var rows [][]T
+	if err := gobUnmarshal(data, &rows); err != nil {
+		return err
+	}
+	return m.setRows(rows)
+}
+
+// MarshalJSON 编码为JSON数组
+func (v PVector[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.ToSlice())
+}
+
+// UnmarshalJSON 从JSON数组恢复向量，null不做任何修改
+// 反序列化需要修改接收者，只应在构造新版本时使用，不要对已经共享给其他goroutine的版本调用
+func (v *PVector[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = *PVectorOf(values...)
+	return nil
+}
+
+// MarshalBinary 编码为二进制
+func (v PVector[T]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(v.ToSlice())
+}
+
+// UnmarshalBinary 从二进制数据恢复向量，注意事项与UnmarshalJSON相同
+func (v *PVector[T]) UnmarshalBinary(data []byte) error {
+	var values []T
+	if err := gobUnmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = *PVectorOf(values...)
+	return nil
+}
+
+// pairs 返回所有键值对
+func (m *PMap[K, V]) pairs() []Pair[K, V] {
+	result := make([]Pair[K, V], 0, m.size)
+	for k, v := range m.All() {
+		result = append(result, Pair[K, V]{Key: k, Value: v})
+	}
+	return result
+}
+
+// setPairs 用pairs构建新的映射并替换接收者
+func (m *PMap[K, V]) setPairs(pairs []Pair[K, V]) {
+	b := NewPMap[K, V]().Transient()
+	for _, p := range pairs {
+		b.Set(p.Key, p.Value)
+	}
+	*m = *b.Persistent()
+}
+
+// MarshalJSON 编码为键值对数组
+func (m PMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.pairs())
+}
+
+// UnmarshalJSON 从键值对数组恢复映射，注意事项与PVector.UnmarshalJSON相同，null不做任何修改
+func (m *PMap[K, V]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var pairs []Pair[K, V]
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+	m.setPairs(pairs)
+	return nil
+}
+
+// MarshalBinary 编码为二进制
+func (m PMap[K, V]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(m.pairs())
+}
+
+// UnmarshalBinary 从二进制数据恢复映射
+func (m *PMap[K, V]) UnmarshalBinary(data []byte) error {
+	var pairs []Pair[K, V]
+	if err := gobUnmarshal(data, &pairs); err != nil {
+		return err
+	}
+	m.setPairs(pairs)
+	return nil
+}
+
+// cacheItem 缓存条目的编码形式
+type cacheItem[K comparable, V any] struct {
+	Key      K         `json:"key"`
+	Value    V         `json:"value"`
+	ExpireAt time.Time `json:"expireAt,omitzero"`
+	Freq     int       `json:"freq,omitempty"`
+}
+
+// items 按淘汰顺序返回所有未过期的条目，最先被淘汰的在前
+func (c *Cache[K, V]) items() []cacheItem[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]cacheItem[K, V], 0, len(c.entries))
+	now := time.Now()
+	appendList := func(list *LinkedList[*cacheEntry[K, V]]) {
+		for n := list.Back(); n != nil; n = n.Prev() {
+			if e := n.Value(); !e.expired(now) {
+				result = append(result, cacheItem[K, V]{Key: e.key, Value: e.value, ExpireAt: e.expireAt, Freq: e.freq})
+			}
+		}
+	}
+	if c.opts.Policy == LFU {
+		for _, freq := range slices.Sorted(maps.Keys(c.freqs)) {
+			appendList(c.freqs[freq])
+		}
+	} else if c.recency != nil {
+		appendList(c.recency)
+	}
+	return result
+}
+
+// setItems 用items替换缓存的全部条目，保留配置和统计信息，不触发淘汰回调
+// 已过期的条目会被丢弃，超出容量时淘汰排在前面的条目，重复的键以后出现的为准
+func (c *Cache[K, V]) setItems(items []cacheItem[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reset()
+	// 反序列化替换了全部内容，正在进行的加载不再写入
+	for key := range c.loading {
+		c.supersedeLoad(key)
+	}
+	now := time.Now()
+	for _, item := range items {
+		entry := &cacheEntry[K, V]{key: item.Key, value: item.Value, expireAt: item.ExpireAt}
+		if entry.expired(now) {
+			continue
+		}
+		if old, ok := c.entries[item.Key]; ok {
+			c.remove(old)
+		}
+		c.entries[item.Key] = entry
+		if c.opts.Policy == LFU {
+			entry.freq = max(item.Freq, 1)
+			entry.node = c.freqList(entry.freq).PushFront(entry)
+		} else {
+			entry.node = c.recency.PushFront(entry)
+		}
+	}
+	if c.opts.Policy == LFU {
+		c.recomputeMinFreq()
+	}
+	for c.opts.MaxSize > 0 && len(c.entries) > c.opts.MaxSize {
+		c.remove(c.victim())
+	}
+}
+
+// MarshalJSON 按淘汰顺序编码未过期的条目，LFU缓存同时保存访问次数
+// Cache包含读写锁，因此使用指针接收者，按值保存在结构体中时需要编码结构体的指针
+func (c *Cache[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.items())
+}
+
+// UnmarshalJSON 从条目数组恢复缓存，原有条目会被清空，null不做任何修改
+// 配置沿用接收者的配置，零值缓存使用默认配置（LRU、不限容量）
+func (c *Cache[K, V]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var items []cacheItem[K, V]
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	c.setItems(items)
+	return nil
+}
+
+// MarshalBinary 编码为二进制
+func (c *Cache[K, V]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(c.items())
+}
+
+// UnmarshalBinary 从二进制数据恢复缓存，规则与UnmarshalJSON相同
+func (c *Cache[K, V]) UnmarshalBinary(data []byte) error {
+	var items []cacheItem[K, V]
+	if err := gobUnmarshal(data, &items); err != nil {
+		return err
+	}
+	c.setItems(items)
+	return nil
+}
+
+// graphData 图的编码形式
+type graphData[K comparable, W Numberish] struct {
+	Directed bool         `json:"directed"`
+	Vertices []K          `json:"vertices"`
+	Edges    []Edge[K, W] `json:"edges"`
+}
+
+// data 返回图的编码形式，顶点按加入顺序排列以保留孤立顶点
+func (g *Graph[K, W]) data() graphData[K, W] {
+	return graphData[K, W]{Directed: g.directed, Vertices: g.Vertices(), Edges: g.Edges()}
+}
+
+// setData 从编码形式重建图
+func (g *Graph[K, W]) setData(d graphData[K, W]) {
+	*g = *NewGraph[K, W](d.Directed)
+	for _, v := range d.Vertices {
+		g.AddVertex(v)
+	}
+	for _, e := range d.Edges {
+		g.AddEdge(e.From, e.To, e.Weight)
+	}
+}
+
+// MarshalJSON 编码为包含方向、顶点和边的JSON对象，无向图的每条边只出现一次
+func (g Graph[K, W]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.data())
+}
+
+// UnmarshalJSON 从JSON对象恢复图，null不做任何修改
+func (g *Graph[K, W]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var d graphData[K, W]
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	g.setData(d)
+	return nil
+}
+
+// MarshalBinary 编码为二进制
+func (g Graph[K, W]) MarshalBinary() ([]byte, error) {
+	return gobMarshal(g.data())
+}
+
+// UnmarshalBinary 从二进制数据恢复图
+func (g *Graph[K, W]) UnmarshalBinary(data []byte) error {
+	var d graphData[K, W]
+	if err := gobUnmarshal(data, &d); err != nil {
+		return err
+	}
+	g.setData(d)
+	return nil
+}
+
+// MarshalJSON 编码为二进制形式的base64字符串，哈希函数不会被编码
+func (f BloomFilter[T]) MarshalJSON() ([]byte, error) {
+	data, err := f.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON 从base64字符串恢复布隆过滤器，规则与UnmarshalBinary相同，null不做任何修改
+func (f *BloomFilter[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var b []byte
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	return f.UnmarshalBinary(b)
+}
+
+// MarshalJSON 编码为二进制形式的base64字符串，哈希函数不会被编码
+func (s CountMinSketch[T]) MarshalJSON() ([]byte, error) {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON 从base64字符串恢复草图，规则与UnmarshalBinary相同，null不做任何修改
+func (s *CountMinSketch[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var b []byte
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(b)
+}
+
+// 30.1 容器编码演示
+func demonstrateEncoding() {
+	fmt.Println("\n=== 容器编码演示 ===")
+
+	// 包含多种容器的状态，可以直接交给json.Marshal或gin的c.JSON
+	type Workspace struct {
+		Name    string                `json:"name"`
+		Undo    Stack[string]         `json:"undo"`
+		History LinkedList[string]    `json:"history"`
+		Scores  TreeMap[string, int]  `json:"scores"`
+		Recent  Ring[int]             `json:"recent"`
+		Tags    Set[string]           `json:"tags"`
+		Routes  RadixTree[string]     `json:"routes"`
+		Weights Matrix[float64]       `json:"weights"`
+		Tasks   *PriorityQueue[Job]   `json:"tasks"`
+		Config  *PMap[string, string] `json:"config"`
+	}
+
+	ws := Workspace{Name: "演示空间", Tags: *NewSet("go", "泛型")}
+	ws.Undo.Push("输入")
+	ws.Undo.Push("删除")
+	ws.History.Add("打开")
+	ws.History.Add("保存")
+	ws.Scores.Put("李四", 95)
+	ws.Scores.Put("张三", 88)
+	ws.Recent = *NewRing[int](3, Overwrite)
+	for i := 1; i <= 4; i++ {
+		ws.Recent.Push(i)
+	}
+	ws.Routes = *NewRadixTree[string]()
+	ws.Routes.Insert("/api", "API")
+	weights, _ := MatrixFromRows([][]float64{{1, 2}, {3, 4}})
+	ws.Weights = *weights
+	ws.Tasks = NewPriorityQueue[Job](MinHeap)
+	now := time.Now()
+	ws.Tasks.Push(Job{Name: "发布", Deadline: now.Add(3 * time.Hour)})
+	ws.Tasks.Push(Job{Name: "修复", Deadline: now.Add(time.Hour)})
+	ws.Config = NewPMap[string, string]().Set("env", "prod")
+
+	jsonData, err := json.Marshal(ws)
+	if err != nil {
+		fmt.Printf("序列化JSON失败: %v\n", err)
+		return
+	}
+	fmt.Printf("JSON: %s\n", jsonData)
+
+	path := filepath.Join(os.TempDir(), "workspace.json")
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		fmt.Printf("写入JSON文件失败: %v\n", err)
+		return
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("读取JSON文件失败: %v\n", err)
+		return
+	}
+	// 优先队列需要比较函数，反序列化前先创建好
+	loaded := Workspace{Tasks: NewPriorityQueue[Job](MinHeap)}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		fmt.Printf("反序列化JSON失败: %v\n", err)
+		return
+	}
+	top, _ := loaded.Undo.Pop()
+	score, _ := loaded.Scores.Get("张三")
+	next, _ := loaded.Tasks.Pop()
+	det, _ := Determinant(&loaded.Weights)
+	env, _ := loaded.Config.Get("env")
+	fmt.Printf("从JSON文件加载: 栈顶=%s 历史=%v 张三=%d 最近=%v 标签=%v 路由数=%d 行列式=%.0f 下一个任务=%s env=%s\n",
+		top, loaded.History.ToSlice(), score, loaded.Recent.ToSlice(), SortedValues(&loaded.Tags),
+		loaded.Routes.Len(), det, next.Name, env)
+
+	// 二进制编码
+	var dq Deque[string]
+	dq.PushBack("b")
+	dq.PushFront("a")
+	dq.PushBack("c")
+	bin, err := dq.MarshalBinary()
+	if err != nil {
+		fmt.Printf("二进制编码失败: %v\n", err)
+		return
+	}
+	var restored Deque[string]
+	if err := restored.UnmarshalBinary(bin); err != nil {
+		fmt.Printf("二进制解码失败: %v\n", err)
+		return
+	}
+	fmt.Printf("Deque二进制编码%d字节, 解码后: %v\n", len(bin), restored.ToSlice())
+
+	// 缓存按淘汰顺序保存条目，恢复后淘汰顺序不变
+	sessions := NewCache[string, int](CacheOptions[string, int]{MaxSize: 3})
+	sessions.Set("a", 1)
+	sessions.Set("b", 2)
+	sessions.Set("c", 3)
+	sessions.Get("a")
+	cacheJSON, _ := json.Marshal(sessions)
+	reloaded := NewCache[string, int](CacheOptions[string, int]{MaxSize: 3})
+	if err := json.Unmarshal(cacheJSON, reloaded); err != nil {
+		fmt.Printf("反序列化缓存失败: %v\n", err)
+		return
+	}
+	reloaded.Set("d", 4)
+	_, hasB := reloaded.Peek("b")
+	fmt.Printf("缓存JSON: %s, 恢复后写入d, b仍在缓存中: %v\n", cacheJSON, hasB)
+
+	roads := NewGraph[string, int](false)
+	roads.AddEdge("北京", "天津", 120)
+	roads.AddEdge("天津", "济南", 300)
+	roads.AddVertex("拉萨")
+	graphJSON, _ := json.Marshal(roads)
+	var loadedRoads Graph[string, int]
+	if err := json.Unmarshal(graphJSON, &loadedRoads); err != nil {
+		fmt.Printf("反序列化图失败: %v\n", err)
+		return
+	}
+	route, _ := Dijkstra(&loadedRoads, "北京", "济南")
+	fmt.Printf("图JSON: %s, 北京->济南: %v\n", graphJSON, route)
+
+	var noLess PriorityQueue[int]
+	if err := json.Unmarshal([]byte("[3,1,2]"), &noLess); err != nil {
+		fmt.Println("反序列化失败:", err)
+	}
+}
@@ -0,0 +1,746 @@
+package main
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// codec 同时支持JSON和二进制编解码的容器指针
+type codec interface {
+	json.Marshaler
+	json.Unmarshaler
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// encodingCase 一种容器的编码测试用例，容器中保存的都是int，contents按容器的自然顺序返回这些值
+type encodingCase struct {
+	name     string
+	make     func(values []int) codec
+	zero     func() codec // 零值容器，为nil表示零值不能直接反序列化
+	contents func(c codec) []int
+}
+
+// encodingCases 覆盖所有实现了编码的容器
+func encodingCases() []encodingCase {
+	intLess := func(a, b int) bool { return a < b }
+	return []encodingCase{
+		{
+			name: "栈",
+			make: func(values []int) codec {
+				s := &Stack[int]{}
+				for _, v := range values {
+					s.Push(v)
+				}
+				return s
+			},
+			zero:     func() codec { return &Stack[int]{} },
+			contents: func(c codec) []int { return slices.Clone(c.(*Stack[int]).elements) },
+		},
+		{
+			name: "链表",
+			make: func(values []int) codec {
+				l := NewLinkedList[int]()
+				for _, v := range values {
+					l.Add(v)
+				}
+				return l
+			},
+			zero:     func() codec { return &LinkedList[int]{} },
+			contents: func(c codec) []int { return c.(*LinkedList[int]).ToSlice() },
+		},
+		{
+			name: "有序映射",
+			make: func(values []int) codec {
+				m := NewTreeMap[int, int]()
+				for _, v := range values {
+					m.Put(v, v)
+				}
+				return m
+			},
+			zero: func() codec { return &TreeMap[int, int]{} },
+			contents: func(c codec) []int {
+				var result []int
+				for p := range c.(*TreeMap[int, int]).All() {
+					result = append(result, p.Value)
+				}
+				return result
+			},
+		},
+		{
+			name:     "集合",
+			make:     func(values []int) codec { return NewSet(values...) },
+			zero:     func() codec { return &Set[int]{} },
+			contents: func(c codec) []int { return SortedValues(c.(*Set[int])) },
+		},
+		{
+			name: "优先队列",
+			make: func(values []int) codec {
+				pq := NewPriorityQueueFunc(intLess, MinHeap)
+				for _, v := range values {
+					pq.Push(v)
+				}
+				return pq
+			},
+			contents: func(c codec) []int { return slices.Sorted(slices.Values(c.(*PriorityQueue[int]).values())) },
+		},
+		{
+			name: "环形缓冲区",
+			make: func(values []int) codec {
+				r := NewRing[int](128, Reject)
+				for _, v := range values {
+					r.Push(v)
+				}
+				return r
+			},
+			zero:     func() codec { return &Ring[int]{} },
+			contents: func(c codec) []int { return c.(*Ring[int]).ToSlice() },
+		},
+		{
+			name: "双端队列",
+			make: func(values []int) codec {
+				d := NewDeque[int](0)
+				for _, v := range values {
+					d.PushBack(v)
+				}
+				return d
+			},
+			zero:     func() codec { return &Deque[int]{} },
+			contents: func(c codec) []int { return c.(*Deque[int]).ToSlice() },
+		},
+		{
+			name: "跳表",
+			make: func(values []int) codec {
+				s := NewSkipList[int, int]()
+				for _, v := range values {
+					s.Put(v, v)
+				}
+				return s
+			},
+			zero: func() codec { return &SkipList[int, int]{} },
+			contents: func(c codec) []int {
+				var result []int
+				for p := range c.(*SkipList[int, int]).All() {
+					result = append(result, p.Value)
+				}
+				return result
+			},
+		},
+		{
+			name: "基数树",
+			make: func(values []int) codec {
+				r := NewRadixTree[int]()
+				for _, v := range values {
+					r.Insert(strconv.Itoa(v), v)
+				}
+				return r
+			},
+			zero: func() codec { return &RadixTree[int]{} },
+			contents: func(c codec) []int {
+				return slices.Sorted(maps.Values(c.(*RadixTree[int]).toMap()))
+			},
+		},
+		{
+			name: "字节基数树",
+			make: func(values []int) codec {
+				r := NewByteRadixTree[int]()
+				for _, v := range values {
+					r.Insert([]byte(strconv.Itoa(v)), v)
+				}
+				return r
+			},
+			zero: func() codec { return &ByteRadixTree[int]{} },
+			contents: func(c codec) []int {
+				var result []int
+				for _, p := range c.(*ByteRadixTree[int]).pairs() {
+					if string(p.Key) != strconv.Itoa(p.Value) {
+						return nil
+					}
+					result = append(result, p.Value)
+				}
+				slices.Sort(result)
+				return result
+			},
+		},
+		{
+			name: "矩阵",
+			make: func(values []int) codec {
+				rows := make([][]int, len(values))
+				for i, v := range values {
+					rows[i] = []int{v, -v}
+				}
+				m, _ := MatrixFromRows(rows)
+				return m
+			},
+			zero: func() codec { return &Matrix[int]{} },
+			contents: func(c codec) []int {
+				m := c.(*Matrix[int])
+				var result []int
+				for i := range m.rows {
+					if row := m.Row(i); len(row) == 2 && row[1] == -row[0] {
+						result = append(result, row[0])
+					}
+				}
+				return result
+			},
+		},
+		{
+			name:     "持久化向量",
+			make:     func(values []int) codec { return PVectorOf(values...) },
+			zero:     func() codec { return &PVector[int]{} },
+			contents: func(c codec) []int { return c.(*PVector[int]).ToSlice() },
+		},
+		{
+			name: "持久化映射",
+			make: func(values []int) codec {
+				m := NewPMap[int, int]()
+				for _, v := range values {
+					m = m.Set(v, v)
+				}
+				return m
+			},
+			zero: func() codec { return &PMap[int, int]{} },
+			contents: func(c codec) []int {
+				return slices.Sorted(maps.Values(maps.Collect(c.(*PMap[int, int]).All())))
+			},
+		},
+		{
+			name: "缓存",
+			make: func(values []int) codec {
+				c := NewCache(CacheOptions[int, int]{})
+				for _, v := range values {
+					c.Set(v, v)
+				}
+				return c
+			},
+			zero: func() codec { return &Cache[int, int]{} },
+			contents: func(c codec) []int {
+				var result []int
+				for _, item := range c.(*Cache[int, int]).items() {
+					result = append(result, item.Value)
+				}
+				return result
+			},
+		},
+		{
+			name: "图",
+			make: func(values []int) codec {
+				g := NewGraph[int, int](true)
+				for _, v := range values {
+					g.AddVertex(v)
+				}
+				return g
+			},
+			zero:     func() codec { return &Graph[int, int]{} },
+			contents: func(c codec) []int { return c.(*Graph[int, int]).Vertices() },
+		},
+		{
+			// 误判率足够低，在检查范围内不会出现误判
+			name: "布隆过滤器",
+			make: func(values []int) codec {
+				f := NewBloomFilter[int](1000, 1e-9)
+				for _, v := range values {
+					f.Add(v)
+				}
+				return f
+			},
+			zero: func() codec { return &BloomFilter[int]{} },
+			contents: func(c codec) []int {
+				f := c.(*BloomFilter[int])
+				return probeContents(func(v int) bool { return f.Bits() > 0 && f.Contains(v) })
+			},
+		},
+		{
+			name: "Count-Min Sketch",
+			make: func(values []int) codec {
+				s := NewCountMinSketch[int](0.001, 1e-6)
+				for _, v := range values {
+					s.Add(v, 1)
+				}
+				return s
+			},
+			zero: func() codec { return &CountMinSketch[int]{} },
+			contents: func(c codec) []int {
+				s := c.(*CountMinSketch[int])
+				return probeContents(func(v int) bool { return s.Width() > 0 && s.Count(v) > 0 })
+			},
+		},
+	}
+}
+
+// probeContents 返回[0, 1100)中contains判断为存在的值，用于不能遍历元素的概率数据结构
+func probeContents(contains func(int) bool) []int {
+	var result []int
+	for v := range 1100 {
+		if contains(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// checkContents 比较容器内容，nil和空切片视为相同
+func checkContents(t *testing.T, tc encodingCase, c codec, want []int, what string) {
+	t.Helper()
+	if got := tc.contents(c); !slices.Equal(got, want) {
+		t.Errorf("%s: contents = %v, want %v", what, got, want)
+	}
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	for _, tc := range encodingCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, n := range []int{0, 1, 100} {
+				var want []int
+				for i := range n {
+					want = append(want, i)
+				}
+				src := tc.make(want)
+				jsonData, err := src.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+				binData, err := src.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// 反序列化会替换原有内容，而不是合并
+				fromJSON := tc.make([]int{1000, 1001})
+				if err := fromJSON.UnmarshalJSON(jsonData); err != nil {
+					t.Fatalf("UnmarshalJSON(%s) = %v", jsonData, err)
+				}
+				checkContents(t, tc, fromJSON, want, "JSON into a non-empty container")
+				fromBinary := tc.make([]int{1000, 1001})
+				if err := fromBinary.UnmarshalBinary(binData); err != nil {
+					t.Fatal(err)
+				}
+				checkContents(t, tc, fromBinary, want, "binary into a non-empty container")
+
+				if tc.zero == nil {
+					continue
+				}
+				zero := tc.zero()
+				if err := zero.UnmarshalJSON(jsonData); err != nil {
+					t.Fatalf("UnmarshalJSON into zero value = %v", err)
+				}
+				checkContents(t, tc, zero, want, "JSON into zero value")
+				zero = tc.zero()
+				if err := zero.UnmarshalBinary(binData); err != nil {
+					t.Fatalf("UnmarshalBinary into zero value = %v", err)
+				}
+				checkContents(t, tc, zero, want, "binary into zero value")
+			}
+			if tc.zero != nil {
+				// 零值容器编码后能够原样读回
+				jsonData, err := tc.zero().MarshalJSON()
+				if err != nil {
+					t.Fatalf("MarshalJSON of zero value = %v", err)
+				}
+				if err := tc.zero().UnmarshalJSON(jsonData); err != nil {
+					t.Errorf("UnmarshalJSON(%s) of zero value = %v", jsonData, err)
+				}
+				binData, err := tc.zero().MarshalBinary()
+				if err != nil {
+					t.Fatalf("MarshalBinary of zero value = %v", err)
+				}
+				if err := tc.zero().UnmarshalBinary(binData); err != nil {
+					t.Errorf("UnmarshalBinary of zero value = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodingNull(t *testing.T) {
+	// 按encoding/json的约定，null不修改接收者
+	for _, tc := range encodingCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.make([]int{7, 8})
+			if err := c.UnmarshalJSON([]byte("null")); err != nil {
+				t.Fatal(err)
+			}
+			checkContents(t, tc, c, []int{7, 8}, "after null")
+		})
+	}
+
+	// 作为非指针的结构体字段时，encoding/json会把null交给UnmarshalJSON
+	type Fields struct {
+		Stack  Stack[int]
+		List   LinkedList[int]
+		Tree   TreeMap[int, int]
+		Set    Set[int]
+		Deque  Deque[int]
+		Skip   SkipList[int, int]
+		Radix  RadixTree[int]
+		Vector PVector[int]
+		Map    PMap[int, int]
+		Cache  Cache[int, int]
+		Graph  Graph[int, int]
+	}
+	var f Fields
+	f.Stack.Push(1)
+	f.List.Add(1)
+	f.Tree.Put(1, 1)
+	f.Set.Add(1)
+	f.Deque.PushBack(1)
+	f.Skip.setPairs([]Pair[int, int]{{Key: 1, Value: 1}})
+	f.Radix = *NewRadixTree[int]()
+	f.Radix.Insert("1", 1)
+	f.Vector = *PVectorOf(1)
+	f.Map = *NewPMap[int, int]().Set(1, 1)
+	f.Cache.setItems([]cacheItem[int, int]{{Key: 1, Value: 1}})
+	f.Graph = *NewGraph[int, int](true)
+	f.Graph.AddVertex(1)
+
+	data := `{"Stack":null,"List":null,"Tree":null,"Set":null,"Deque":null,"Skip":null,
+		"Radix":null,"Vector":null,"Map":null,"Cache":null,"Graph":null}`
+	if err := json.Unmarshal([]byte(data), &f); err != nil {
+		t.Fatal(err)
+	}
+	sizes := map[string]int{
+		"Stack": f.Stack.Size(), "List": f.List.Size(), "Tree": f.Tree.Size(), "Set": f.Set.Size(),
+		"Deque": f.Deque.Len(), "Skip": f.Skip.Size(), "Radix": f.Radix.Len(), "Vector": f.Vector.Len(),
+		"Map": f.Map.Len(), "Cache": f.Cache.Len(), "Graph": len(f.Graph.Vertices()),
+	}
+	for name, size := range sizes {
+		if size != 1 {
+			t.Errorf("%s field has %d elements after null, want 1", name, size)
+		}
+	}
+}
+
+func TestSkipListFieldDecoding(t *testing.T) {
+	// 结构体中的零值跳表可以直接反序列化，之后正常使用
+	type Leaderboard struct {
+		Name   string
+		Scores SkipList[int, string]
+	}
+	scores := NewSkipList[int, string]()
+	for i := 0; i < 50; i++ {
+		scores.Put(i*10, strconv.Itoa(i))
+	}
+	data, err := json.Marshal(map[string]any{"Name": "周榜", "Scores": scores})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst Leaderboard
+	if err := json.Unmarshal(data, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Scores.Size() != 50 {
+		t.Fatalf("Size() = %d, want 50", dst.Scores.Size())
+	}
+	if rank, ok := dst.Scores.Rank(250); !ok || rank != 25 {
+		t.Errorf("Rank(250) = %d, %v", rank, ok)
+	}
+	dst.Scores.Put(5, "new")
+	if p, ok := dst.Scores.Select(1); !ok || p.Key != 5 {
+		t.Errorf("Select(1) = %v, %v", p, ok)
+	}
+
+	// 再次反序列化更短的数据，原有的高层索引也要清空
+	if err := json.Unmarshal([]byte(`{"Scores":[{"Key":3,"Value":"c"},{"Key":1,"Value":"a"}]}`), &dst); err != nil {
+		t.Fatal(err)
+	}
+	var keys []int
+	for p := range dst.Scores.All() {
+		keys = append(keys, p.Key)
+	}
+	if !slices.Equal(keys, []int{1, 3}) || dst.Scores.Size() != 2 {
+		t.Errorf("after replacing: keys %v, Size() %d", keys, dst.Scores.Size())
+	}
+	if p, ok := dst.Scores.Select(1); !ok || p.Key != 3 {
+		t.Errorf("Select(1) after replacing = %v, %v", p, ok)
+	}
+	if _, ok := dst.Scores.Get(250); ok {
+		t.Error("old key survived replacement")
+	}
+}
+
+func TestCacheEncoding(t *testing.T) {
+	t.Run("LRU顺序", func(t *testing.T) {
+		src := NewCache(CacheOptions[string, int]{MaxSize: 3})
+		src.Set("a", 1)
+		src.Set("b", 2)
+		src.Set("c", 3)
+		src.Get("a")
+		data, err := json.Marshal(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var evictedKeys []string
+		dst := NewCache(CacheOptions[string, int]{
+			MaxSize: 3,
+			OnEvict: func(k string, _ int, _ EvictReason) { evictedKeys = append(evictedKeys, k) },
+		})
+		dst.Set("old", 0)
+		if err := json.Unmarshal(data, dst); err != nil {
+			t.Fatal(err)
+		}
+		if len(evictedKeys) != 0 {
+			t.Fatalf("decoding fired OnEvict for %v", evictedKeys)
+		}
+		// b是最久未访问的，写入新条目时应当被淘汰
+		dst.Set("d", 4)
+		if !slices.Equal(evictedKeys, []string{"b"}) {
+			t.Errorf("evicted %v, want [b]", evictedKeys)
+		}
+		if _, ok := dst.Peek("old"); ok {
+			t.Error("entry from before decoding survived")
+		}
+	})
+
+	t.Run("LFU次数", func(t *testing.T) {
+		src := NewCache(CacheOptions[string, int]{MaxSize: 3, Policy: LFU})
+		src.Set("hot", 1)
+		src.Set("warm", 2)
+		src.Set("cold", 3)
+		for range 5 {
+			src.Get("hot")
+		}
+		src.Get("warm")
+		data, err := src.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := NewCache(CacheOptions[string, int]{MaxSize: 3, Policy: LFU})
+		if err := dst.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		got := dst.items()
+		want := []cacheItem[string, int]{{Key: "cold", Value: 3, Freq: 1}, {Key: "warm", Value: 2, Freq: 2}, {Key: "hot", Value: 1, Freq: 6}}
+		if !slices.Equal(got, want) {
+			t.Fatalf("items = %+v, want %+v", got, want)
+		}
+		dst.Set("new", 4)
+		if _, ok := dst.Peek("cold"); ok {
+			t.Error("least frequently used entry was not evicted")
+		}
+	})
+
+	t.Run("过期与容量", func(t *testing.T) {
+		expireAt := time.Now().Add(time.Hour).Round(0)
+		items := []cacheItem[string, int]{
+			{Key: "expired", Value: 0, ExpireAt: time.Now().Add(-time.Second)},
+			{Key: "a", Value: 1},
+			{Key: "b", Value: 2, ExpireAt: expireAt},
+			{Key: "a", Value: 10},
+			{Key: "c", Value: 3},
+		}
+		data, err := json.Marshal(items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 重复的键以后出现的为准，恢复后的淘汰顺序为b、a、c，容量为2时淘汰b
+		dst := NewCache(CacheOptions[string, int]{MaxSize: 2})
+		if err := json.Unmarshal(data, dst); err != nil {
+			t.Fatal(err)
+		}
+		got := dst.items()
+		want := []cacheItem[string, int]{{Key: "a", Value: 10}, {Key: "c", Value: 3}}
+		if !slices.Equal(got, want) {
+			t.Fatalf("items = %+v, want %+v", got, want)
+		}
+
+		var unbounded Cache[string, int]
+		if err := json.Unmarshal(data, &unbounded); err != nil {
+			t.Fatal(err)
+		}
+		if unbounded.Len() != 3 {
+			t.Fatalf("Len() = %d, want 3", unbounded.Len())
+		}
+		if _, ok := unbounded.Peek("expired"); ok {
+			t.Error("expired entry was restored")
+		}
+		if e := unbounded.entries["b"]; e == nil || !e.expireAt.Equal(expireAt) {
+			t.Errorf("expiry of b not restored: %+v", e)
+		}
+		unbounded.Set("d", 4)
+		if v, ok := unbounded.Get("d"); !ok || v != 4 {
+			t.Errorf("zero value cache unusable after decoding: %d, %v", v, ok)
+		}
+	})
+
+	t.Run("保留统计", func(t *testing.T) {
+		c := NewCache(CacheOptions[string, int]{})
+		c.Set("a", 1)
+		c.Get("a")
+		c.Get("missing")
+		if err := c.UnmarshalJSON([]byte(`[{"key":"b","value":2}]`)); err != nil {
+			t.Fatal(err)
+		}
+		if s := c.Stats(); s.Hits != 1 || s.Misses != 1 {
+			t.Errorf("Stats() = %+v", s)
+		}
+	})
+}
+
+// sortedEdges 按终点和权重排序边
+func sortedEdges(edges []Edge[string, float64]) []Edge[string, float64] {
+	slices.SortFunc(edges, func(a, b Edge[string, float64]) int {
+		return cmp.Or(cmp.Compare(a.To, b.To), cmp.Compare(a.Weight, b.Weight))
+	})
+	return edges
+}
+
+func TestGraphEncoding(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		g := NewGraph[string, float64](directed)
+		g.AddEdge("a", "b", 1.5)
+		g.AddEdge("b", "c", 2)
+		g.AddEdge("a", "b", 3) // 平行边
+		g.AddEdge("c", "c", 0) // 自环
+		g.AddVertex("isolated")
+
+		jsonData, err := json.Marshal(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		binData, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromJSON, fromBinary Graph[string, float64]
+		if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+			t.Fatal(err)
+		}
+		if err := fromBinary.UnmarshalBinary(binData); err != nil {
+			t.Fatal(err)
+		}
+		for _, got := range []*Graph[string, float64]{&fromJSON, &fromBinary} {
+			if got.Directed() != directed {
+				t.Errorf("Directed() = %v, want %v", got.Directed(), directed)
+			}
+			if !slices.Equal(got.Vertices(), g.Vertices()) {
+				t.Errorf("Vertices() = %v, want %v", got.Vertices(), g.Vertices())
+			}
+			if !slices.Equal(got.Edges(), g.Edges()) {
+				t.Errorf("Edges() = %v, want %v", got.Edges(), g.Edges())
+			}
+			// 无向图反向边的顺序可能不同，按终点和权重排序后比较
+			for _, v := range g.Vertices() {
+				gotEdges, wantEdges := sortedEdges(got.Neighbors(v)), sortedEdges(g.Neighbors(v))
+				if !slices.Equal(gotEdges, wantEdges) {
+					t.Errorf("directed=%v: Neighbors(%s) = %v, want %v", directed, v, gotEdges, wantEdges)
+				}
+			}
+		}
+	}
+}
+
+func TestLockedContainerFields(t *testing.T) {
+	// SkipList和Cache只有指针接收者的MarshalJSON：按值保存的字段需要编码结构体的指针
+	type Index struct {
+		Scores SkipList[string, int]
+		Recent Cache[string, int]
+	}
+	var src Index
+	if err := json.Unmarshal([]byte(`{"Scores":[{"Key":"b","Value":2},{"Key":"a","Value":1}],
+		"Recent":[{"key":"x","value":7}]}`), &src); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst Index
+	if err := json.Unmarshal(data, &dst); err != nil {
+		t.Fatalf("Unmarshal(%s) = %v", data, err)
+	}
+	if got := dst.Scores.pairs(); !slices.Equal(got, []Pair[string, int]{{"a", 1}, {"b", 2}}) {
+		t.Errorf("Scores = %v", got)
+	}
+	if v, ok := dst.Recent.Get("x"); !ok || v != 7 {
+		t.Errorf("Recent.Get(x) = %d, %v", v, ok)
+	}
+
+	// 保存指针的字段按值编码结构体也能得到完整内容
+	type Refs struct {
+		Scores *SkipList[string, int]
+		Recent *Cache[string, int]
+	}
+	data, err = json.Marshal(Refs{Scores: &src.Scores, Recent: &src.Recent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := Refs{Scores: NewSkipList[string, int](), Recent: NewCache(CacheOptions[string, int]{})}
+	if err := json.Unmarshal(data, &refs); err != nil {
+		t.Fatalf("Unmarshal(%s) = %v", data, err)
+	}
+	if refs.Scores.Size() != 2 || refs.Recent.Len() != 1 {
+		t.Errorf("pointer fields: Size() = %d, Len() = %d", refs.Scores.Size(), refs.Recent.Len())
+	}
+}
+
+func TestProbabilisticJSON(t *testing.T) {
+	// 作为结构体字段并且按值编码时不能退化为{}
+	type Stats struct {
+		Seen BloomFilter[string]
+		Hits CountMinSketch[string]
+	}
+	in := Stats{Seen: *NewBloomFilter[string](100, 0.01), Hits: *NewCountMinSketch[string](0.01, 0.01)}
+	in.Seen.Add("/index")
+	in.Hits.Add("/index", 3)
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Stats
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal(%s) = %v", data, err)
+	}
+	if !out.Seen.Contains("/index") || out.Seen.Bits() != in.Seen.Bits() || out.Seen.HashCount() != in.Seen.HashCount() {
+		t.Errorf("Bloom filter after round trip: Contains = %v, Bits = %d, HashCount = %d",
+			out.Seen.Contains("/index"), out.Seen.Bits(), out.Seen.HashCount())
+	}
+	if out.Hits.Count("/index") != 3 || out.Hits.Total() != 3 {
+		t.Errorf("sketch after round trip: Count = %d, Total = %d", out.Hits.Count("/index"), out.Hits.Total())
+	}
+
+	if err := out.Seen.UnmarshalJSON([]byte(`"QkxNMQ=="`)); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("UnmarshalJSON of a truncated filter = %v", err)
+	}
+	if err := out.Hits.UnmarshalJSON([]byte(`[1,2]`)); err == nil {
+		t.Error("UnmarshalJSON of an array succeeded")
+	}
+}
+
+func TestEncodingErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		dst  json.Unmarshaler
+	}{
+		{"环形缓冲区元素超出容量", `{"capacity":1,"policy":0,"values":[1,2]}`, &Ring[int]{}},
+		{"环形缓冲区容量为负数", `{"capacity":-1,"values":[]}`, &Ring[int]{}},
+		{"容量为0的环形缓冲区有元素", `{"capacity":0,"values":[1]}`, &Ring[int]{}},
+		{"矩阵行长度不同", `[[1,2],[3]]`, &Matrix[int]{}},
+		{"优先队列没有比较函数", `[1,2]`, &PriorityQueue[int]{}},
+		{"类型不匹配", `{"a":1}`, &SkipList[int, int]{}},
+		{"缓存条目格式错误", `[1,2]`, &Cache[string, int]{}},
+		{"图格式错误", `[]`, &Graph[string, int]{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.dst.UnmarshalJSON([]byte(tt.data)); err == nil {
+				t.Errorf("UnmarshalJSON(%s) succeeded", tt.data)
+			}
+		})
+	}
+	var pq PriorityQueue[int]
+	if err := pq.UnmarshalJSON([]byte(`[1]`)); !errors.Is(err, ErrNoComparator) {
+		t.Errorf("UnmarshalJSON without comparator = %v", err)
+	}
+	var r Ring[int]
+	if err := r.UnmarshalJSON([]byte(`{"capacity":1,"values":[1,2]}`)); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("UnmarshalJSON of an overfull ring = %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package main
 
 import (
 	"fmt"
+	"slices"
 )
 
 // 自定义约束接口，替代标准库中的constraints包
@@ -122,7 +123,7 @@ type Comparable[T any] interface {
 // 6. 泛型与方法的组合
 // 泛型类型可以有方法，这些方法可以使用类型的参数
 
-// LinkedList 泛型链表实现
+// LinkedList 泛型双向链表实现
 type LinkedList[T any] struct {
 	head *Node[T]
 	tail *Node[T]
@@ -133,6 +134,8 @@ type LinkedList[T any] struct {
 type Node[T any] struct {
 	value T
 	next  *Node[T]
+	prev  *Node[T]
+	list  *LinkedList[T] // 节点所属的链表，节点被移除后为nil
 }
 
 // NewLinkedList 创建新的链表
@@ -142,31 +145,17 @@ func NewLinkedList[T any]() *LinkedList[T] {
 
 // Add 在链表末尾添加元素
 func (l *LinkedList[T]) Add(value T) {
-	node := &Node[T]{value: value}
-
-	if l.head == nil {
-		l.head = node
-		l.tail = node
-	} else {
-		l.tail.next = node
-		l.tail = node
-	}
-	l.size++
+	l.PushBack(value)
 }
 
 // Get 获取指定索引的元素
 func (l *LinkedList[T]) Get(index int) (T, bool) {
-	if index < 0 || index >= l.size {
+	node := l.nodeAt(index)
+	if node == nil {
 		var zero T
 		return zero, false
 	}
-
-	current := l.head
-	for i := 0; i < index; i++ {
-		current = current.next
-	}
-
-	return current.value, true
+	return node.value, true
 }
 
 // Size 返回链表大小
@@ -184,32 +173,19 @@ type Pair[K, V any] struct {
 }
 
 // Map 泛型映射函数
+// 基于惰性的MapSeq实现，结果切片按输入长度预先分配
 func Map[T, U any](slice []T, fn func(T) U) []U {
-	result := make([]U, len(slice))
-	for i, v := range slice {
-		result[i] = fn(v)
-	}
-	return result
+	return slices.AppendSeq(make([]U, 0, len(slice)), MapSeq(slices.Values(slice), fn))
 }
 
 // Filter 泛型过滤函数
 func Filter[T any](slice []T, predicate func(T) bool) []T {
-	var result []T
-	for _, v := range slice {
-		if predicate(v) {
-			result = append(result, v)
-		}
-	}
-	return result
+	return slices.Collect(FilterSeq(slices.Values(slice), predicate))
 }
 
 // Reduce 泛型归约函数
 func Reduce[T, U any](slice []T, initial U, fn func(U, T) U) U {
-	result := initial
-	for _, v := range slice {
-		result = fn(result, v)
-	}
-	return result
+	return ReduceSeq(slices.Values(slice), initial, fn)
 }
 
 // 8. 类型推断
@@ -393,4 +369,27 @@ func main() {
 	demonstrateGenericLimitations()
 	demonstrateGenericsWithReflection()
 	demonstratePerformanceConsiderations()
+	demonstrateTreeMap()
+	demonstrateDoublyLinkedList()
+	demonstrateIterators()
+	demonstratePriorityQueue()
+	demonstrateCache()
+	demonstrateSet()
+	demonstrateParallelAlgorithms()
+	demonstrateOptionResult()
+	demonstrateStatistics()
+	demonstrateMatrix()
+	demonstrateSorting()
+	demonstrateGraph()
+	demonstrateRadixTree()
+	demonstrateRingDeque()
+	demonstrateSkipList()
+	demonstrateProbabilistic()
+	demonstratePersistent()
+	demonstrateEncoding()
+	demonstrateRangeQueries()
+	demonstrateCollections()
+	demonstrateDisjointSet()
+	demonstrateDiff()
+	demonstrateChecked()
 }
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// 24. 图
+// 基于邻接表的泛型图，支持有向图和无向图
+// 最短路径等需要比较权重大小的算法要求权重满足Weight约束（复数不能比较大小）
+
+var (
+	// ErrVertexNotFound 顶点不存在
+	ErrVertexNotFound = errors.New("顶点不存在")
+	// ErrNoPath 两个顶点之间没有路径
+	ErrNoPath = errors.New("没有路径")
+	// ErrNegativeWeight Dijkstra算法遇到负权边
+	ErrNegativeWeight = errors.New("存在负权边")
+	// ErrNegativeCycle 存在从起点可达的负权环
+	ErrNegativeCycle = errors.New("存在负权环")
+	// ErrNotDirected 算法只适用于有向图
+	ErrNotDirected = errors.New("不是有向图")
+	// ErrNotUndirected 算法只适用于无向图
+	ErrNotUndirected = errors.New("不是无向图")
+)
+
+// Weight 可比较大小的数值类型约束：Numberish中去掉复数
+type Weight interface {
+	Numberish
+	Ordered
+}
+
+// Edge 图中的一条边
+type Edge[K comparable, W Numberish] struct {
+	From   K
+	To     K
+	Weight W
+}
+
+// CycleError 拓扑排序时发现的环
+type CycleError[K comparable] struct {
+	Cycle []K // 环上的顶点，首尾相同
+}
+
+// Error 实现error接口
+func (e *CycleError[K]) Error() string {
+	return fmt.Sprintf("图中存在环: %v", e.Cycle)
+}
+
+// Graph 泛型图
+type Graph[K comparable, W Numberish] struct {
+	directed bool
+	vertices []K // 按加入顺序保存，保证遍历结果确定
+	adj      map[K][]Edge[K, W]
+}
+
+// NewGraph 创建图，directed为true时创建有向图
+func NewGraph[K comparable, W Numberish](directed bool) *Graph[K, W] {
+	return &Graph[K, W]{directed: directed, adj: make(map[K][]Edge[K, W])}
+}
+
+// Directed 是否为有向图
+func (g *Graph[K, W]) Directed() bool {
+	return g.directed
+}
+
+// AddVertex 添加顶点，已存在时忽略
+func (g *Graph[K, W]) AddVertex(v K) {
+	if _, ok := g.adj[v]; ok {
+		return
+	}
+	g.adj[v] = nil
+	g.vertices = append(g.vertices, v)
+}
+
+// AddEdge 添加边，顶点不存在时自动添加；无向图会同时添加反向边
+func (g *Graph[K, W]) AddEdge(from, to K, weight W) {
+	g.AddVertex(from)
+	g.AddVertex(to)
+	g.adj[from] = append(g.adj[from], Edge[K, W]{From: from, To: to, Weight: weight})
+	if !g.directed && from != to {
+		g.adj[to] = append(g.adj[to], Edge[K, W]{From: to, To: from, Weight: weight})
+	}
+}
+
+// HasVertex 检查顶点是否存在
+func (g *Graph[K, W]) HasVertex(v K) bool {
+	_, ok := g.adj[v]
+	return ok
+}
+
+// HasEdge 检查是否存在from到to的边
+func (g *Graph[K, W]) HasEdge(from, to K) bool {
+	for _, e := range g.adj[from] {
+		if e.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Vertices 按加入顺序返回所有顶点
+func (g *Graph[K, W]) Vertices() []K {
+	return slices.Clone(g.vertices)
+}
+
+// Neighbors 返回从v出发的所有边
+func (g *Graph[K, W]) Neighbors(v K) []Edge[K, W] {
+	return slices.Clone(g.adj[v])
+}
+
+// Edges 返回所有边，无向图中每条边只返回一次
+func (g *Graph[K, W]) Edges() []Edge[K, W] {
+	var edges []Edge[K, W]
+	seen := make(map[K]bool)
+	for _, v := range g.vertices {
+		for _, e := range g.adj[v] {
+			// 无向图中另一端已经处理过的边是反向边
+			if !g.directed && seen[e.To] {
+				continue
+			}
+			edges = append(edges, e)
+		}
+		seen[v] = true
+	}
+	return edges
+}
+
+// BFS 从start开始广度优先遍历可达的顶点
+func (g *Graph[K, W]) BFS(start K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if !g.HasVertex(start) {
+			return
+		}
+		visited := map[K]bool{start: true}
+		queue := []K{start}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			if !yield(v) {
+				return
+			}
+			for _, e := range g.adj[v] {
+				if !visited[e.To] {
+					visited[e.To] = true
+					queue = append(queue, e.To)
+				}
+			}
+		}
+	}
+}
+
+// DFS 从start开始深度优先遍历可达的顶点（先序）
+func (g *Graph[K, W]) DFS(start K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if !g.HasVertex(start) {
+			return
+		}
+		visited := make(map[K]bool)
+		stack := []K{start}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if visited[v] {
+				continue
+			}
+			visited[v] = true
+			if !yield(v) {
+				return
+			}
+			// 逆序入栈，使邻居按添加顺序被访问
+			edges := g.adj[v]
+			for i := len(edges) - 1; i >= 0; i-- {
+				if !visited[edges[i].To] {
+					stack = append(stack, edges[i].To)
+				}
+			}
+		}
+	}
+}
+
+// buildPath 根据前驱表从to回溯到from，返回 (顶点, 累计距离) 的路径
+func buildPath[K comparable, W Numberish](from, to K, prev map[K]K, dist map[K]W) []Pair[K, W] {
+	var path []Pair[K, W]
+	for v := to; ; v = prev[v] {
+		path = append(path, Pair[K, W]{Key: v, Value: dist[v]})
+		if v == from {
+			break
+		}
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Dijkstra 求from到to的最短路径，要求所有边的权重非负
+// 路径中每一项的Key为顶点，Value为从from到该顶点的累计距离
+func Dijkstra[K comparable, W Weight](g *Graph[K, W], from, to K) ([]Pair[K, W], error) {
+	if !g.HasVertex(from) || !g.HasVertex(to) {
+		return nil, ErrVertexNotFound
+	}
+	// 搜索前检查所有边：负权边可能指向已经出队的顶点，搜索过程中发现时结果已经不可靠
+	for _, edges := range g.adj {
+		for _, e := range edges {
+			if e.Weight < 0 {
+				return nil, ErrNegativeWeight
+			}
+		}
+	}
+
+	dist := map[K]W{from: 0}
+	prev := make(map[K]K)
+	done := make(map[K]bool)
+	pq := NewPriorityQueueFunc(func(a, b Pair[K, W]) bool { return a.Value < b.Value }, MinHeap)
+	pq.Push(Pair[K, W]{Key: from, Value: 0})
+
+	for !pq.IsEmpty() {
+		cur, _ := pq.Pop()
+		// 同一个顶点可能多次入队，只处理第一次出队（距离最短）的那次
+		if done[cur.Key] {
+			continue
+		}
+		done[cur.Key] = true
+		if cur.Key == to {
+			return buildPath(from, to, prev, dist), nil
+		}
+		for _, e := range g.adj[cur.Key] {
+			nd := cur.Value + e.Weight
+			if d, ok := dist[e.To]; !ok || nd < d {
+				dist[e.To] = nd
+				prev[e.To] = cur.Key
+				pq.Push(Pair[K, W]{Key: e.To, Value: nd})
+			}
+		}
+	}
+	return nil, ErrNoPath
+}
+
+// BellmanFord 求from到to的最短路径，允许负权边，检测从from可达的负权环
+func BellmanFord[K comparable, W Weight](g *Graph[K, W], from, to K) ([]Pair[K, W], error) {
+	if !g.HasVertex(from) || !g.HasVertex(to) {
+		return nil, ErrVertexNotFound
+	}
+
+	dist := map[K]W{from: 0}
+	prev := make(map[K]K)
+	relax := func() bool {
+		changed := false
+		for _, v := range g.vertices {
+			dv, ok := dist[v]
+			if !ok {
+				continue
+			}
+			for _, e := range g.adj[v] {
+				if d, ok := dist[e.To]; !ok || dv+e.Weight < d {
+					dist[e.To] = dv + e.Weight
+					prev[e.To] = v
+					changed = true
+				}
+			}
+		}
+		return changed
+	}
+
+	// 最短路径最多包含|V|-1条边
+	for i := 0; i < len(g.vertices)-1; i++ {
+		if !relax() {
+			break
+		}
+	}
+	if relax() {
+		return nil, ErrNegativeCycle
+	}
+	if _, ok := dist[to]; !ok {
+		return nil, ErrNoPath
+	}
+	return buildPath(from, to, prev, dist), nil
+}
+
+// TopologicalSort 拓扑排序，存在环时返回*CycleError
+func (g *Graph[K, W]) TopologicalSort() ([]K, error) {
+	if !g.directed {
+		return nil, ErrNotDirected
+	}
+
+	const (
+		white = iota // 未访问
+		gray         // 正在访问，仍在递归栈上
+		black        // 访问完成
+	)
+	color := make(map[K]int)
+	parent := make(map[K]K)
+	order := make([]K, 0, len(g.vertices))
+
+	var visit func(v K) error
+	visit = func(v K) error {
+		color[v] = gray
+		for _, e := range g.adj[v] {
+			switch color[e.To] {
+			case white:
+				parent[e.To] = v
+				if err := visit(e.To); err != nil {
+					return err
+				}
+			case gray:
+				// 遇到栈上的顶点说明存在环，沿parent回溯出环上的顶点
+				cycle := []K{e.To}
+				for u := v; u != e.To; u = parent[u] {
+					cycle = append(cycle, u)
+				}
+				cycle = append(cycle, e.To)
+				slices.Reverse(cycle)
+				return &CycleError[K]{Cycle: cycle}
+			}
+		}
+		color[v] = black
+		order = append(order, v)
+		return nil
+	}
+
+	for _, v := range g.vertices {
+		if color[v] == white {
+			if err := visit(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	slices.Reverse(order)
+	return order, nil
+}
+
+// StronglyConnectedComponents 使用Tarjan算法求强连通分量
+// 无向图中返回的就是连通分量
+func (g *Graph[K, W]) StronglyConnectedComponents() [][]K {
+	index := make(map[K]int)
+	low := make(map[K]int)
+	onStack := make(map[K]bool)
+	var stack []K
+	var components [][]K
+	next := 0
+
+	var strongConnect func(v K)
+	strongConnect = func(v K) {
+		index[v] = next
+		low[v] = next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, e := range g.adj[v] {
+			if _, visited := index[e.To]; !visited {
+				strongConnect(e.To)
+				low[v] = min(low[v], low[e.To])
+			} else if onStack[e.To] {
+				low[v] = min(low[v], index[e.To])
+			}
+		}
+
+		// v是分量的根，弹出整个分量
+		if low[v] == index[v] {
+			var component []K
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, v := range g.vertices {
+		if _, visited := index[v]; !visited {
+			strongConnect(v)
+		}
+	}
+	return components
+}
+
+// MinimumSpanningTree 使用Prim算法求无向图的最小生成树
+// 图不连通时返回最小生成森林，同时返回总权重
+func MinimumSpanningTree[K comparable, W Weight](g *Graph[K, W]) ([]Edge[K, W], W, error) {
+	var total W
+	if g.directed {
+		return nil, total, ErrNotUndirected
+	}
+
+	var tree []Edge[K, W]
+	inTree := make(map[K]bool)
+	pq := NewPriorityQueueFunc(func(a, b Edge[K, W]) bool { return a.Weight < b.Weight }, MinHeap)
+
+	for _, root := range g.vertices {
+		if inTree[root] {
+			continue
+		}
+		inTree[root] = true
+		for _, e := range g.adj[root] {
+			pq.Push(e)
+		}
+		for !pq.IsEmpty() {
+			e, _ := pq.Pop()
+			if inTree[e.To] {
+				continue
+			}
+			inTree[e.To] = true
+			tree = append(tree, e)
+			total += e.Weight
+			for _, next := range g.adj[e.To] {
+				if !inTree[next.To] {
+					pq.Push(next)
+				}
+			}
+		}
+	}
+	return tree, total, nil
+}
+
+// 24.1 图算法演示
+func demonstrateGraph() {
+	fmt.Println("\n=== 图算法演示 ===")
+
+	// 城市之间的道路（无向图，权重为公里数）
+	roads := NewGraph[string, int](false)
+	roads.AddEdge("北京", "天津", 137)
+	roads.AddEdge("北京", "石家庄", 283)
+	roads.AddEdge("天津", "济南", 360)
+	roads.AddEdge("石家庄", "郑州", 412)
+	roads.AddEdge("济南", "郑州", 470)
+	roads.AddEdge("济南", "南京", 617)
+	roads.AddEdge("郑州", "南京", 695)
+
+	fmt.Printf("BFS: %v\n", slices.Collect(roads.BFS("北京")))
+	fmt.Printf("DFS: %v\n", slices.Collect(roads.DFS("北京")))
+
+	if path, err := Dijkstra(roads, "北京", "南京"); err == nil {
+		fmt.Printf("Dijkstra 北京->南京: %v\n", path)
+	}
+
+	tree, total, _ := MinimumSpanningTree(roads)
+	fmt.Printf("最小生成树: 总长度 %d 公里, %d 条边\n", total, len(tree))
+	for _, e := range tree {
+		fmt.Printf("  %s - %s: %d\n", e.From, e.To, e.Weight)
+	}
+
+	// 带负权边的有向图
+	trades := NewGraph[string, float64](true)
+	trades.AddEdge("A", "B", 4)
+	trades.AddEdge("A", "C", 5)
+	trades.AddEdge("C", "B", -3)
+	trades.AddEdge("B", "D", 2)
+	if _, err := Dijkstra(trades, "A", "D"); err != nil {
+		fmt.Printf("Dijkstra: %v\n", err)
+	}
+	if path, err := BellmanFord(trades, "A", "D"); err == nil {
+		fmt.Printf("BellmanFord A->D: %v\n", path)
+	}
+	trades.AddEdge("D", "C", -1)
+	if _, err := BellmanFord(trades, "A", "D"); err != nil {
+		fmt.Printf("BellmanFord: %v\n", err)
+	}
+
+	// 课程依赖的拓扑排序
+	courses := NewGraph[string, int](true)
+	courses.AddEdge("Go基础", "并发编程", 1)
+	courses.AddEdge("Go基础", "泛型", 1)
+	courses.AddEdge("泛型", "数据结构", 1)
+	courses.AddEdge("并发编程", "Web开发", 1)
+	courses.AddEdge("数据结构", "Web开发", 1)
+	order, _ := courses.TopologicalSort()
+	fmt.Printf("学习顺序: %v\n", order)
+
+	courses.AddEdge("Web开发", "Go基础", 1)
+	_, err := courses.TopologicalSort()
+	var cycleErr *CycleError[string]
+	if errors.As(err, &cycleErr) {
+		fmt.Printf("拓扑排序失败: %v\n", cycleErr)
+	}
+
+	fmt.Printf("强连通分量: %v\n", courses.StronglyConnectedComponents())
+}
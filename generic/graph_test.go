@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// pathKeys 返回路径上的顶点
+func pathKeys[K comparable, W Numberish](path []Pair[K, W]) []K {
+	keys := make([]K, len(path))
+	for i, p := range path {
+		keys[i] = p.Key
+	}
+	return keys
+}
+
+func TestDijkstra(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddEdge("A", "B", 4)
+	g.AddEdge("A", "C", 1)
+	g.AddEdge("C", "B", 2)
+	g.AddEdge("B", "D", 5)
+	g.AddVertex("E")
+
+	path, err := Dijkstra(g, "A", "D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := pathKeys(path); !slices.Equal(keys, []string{"A", "C", "B", "D"}) || path[len(path)-1].Value != 8 {
+		t.Errorf("Dijkstra(A, D) = %v, want A→C→B→D with distance 8", path)
+	}
+	if path, _ := Dijkstra(g, "A", "A"); len(path) != 1 || path[0].Value != 0 {
+		t.Errorf("Dijkstra(A, A) = %v", path)
+	}
+	if _, err := Dijkstra(g, "A", "E"); !errors.Is(err, ErrNoPath) {
+		t.Errorf("Dijkstra to unreachable vertex = %v", err)
+	}
+	if _, err := Dijkstra(g, "A", "Z"); !errors.Is(err, ErrVertexNotFound) {
+		t.Errorf("Dijkstra to missing vertex = %v", err)
+	}
+}
+
+func TestDijkstraRejectsNegativeWeights(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges []Edge[string, int]
+	}{
+		// T在U之前出队，U→T的负权边指向已完成的顶点
+		{"指向已出队顶点", []Edge[string, int]{{"A", "T", 5}, {"A", "U", 6}, {"U", "T", -3}}},
+		{"不可达的部分", []Edge[string, int]{{"A", "T", 1}, {"X", "Y", -1}}},
+		{"起点的出边", []Edge[string, int]{{"A", "T", -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGraph[string, int](true)
+			for _, e := range tt.edges {
+				g.AddEdge(e.From, e.To, e.Weight)
+			}
+			if path, err := Dijkstra(g, "A", "T"); !errors.Is(err, ErrNegativeWeight) {
+				t.Errorf("Dijkstra = %v, %v, want ErrNegativeWeight", path, err)
+			}
+		})
+	}
+}
+
+func TestShortestPathsAgree(t *testing.T) {
+	// 非负权重的随机图上Dijkstra与BellmanFord的距离一致
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		g := NewGraph[int, int](round%2 == 0)
+		for range 60 {
+			g.AddEdge(r.Intn(20), r.Intn(20), r.Intn(10))
+		}
+		for to := range 20 {
+			if !g.HasVertex(0) || !g.HasVertex(to) {
+				continue
+			}
+			d, derr := Dijkstra(g, 0, to)
+			b, berr := BellmanFord(g, 0, to)
+			if !errors.Is(derr, berr) {
+				t.Fatalf("errors differ: %v vs %v", derr, berr)
+			}
+			if derr == nil && d[len(d)-1].Value != b[len(b)-1].Value {
+				t.Fatalf("distance 0→%d: Dijkstra %d, BellmanFord %d", to, d[len(d)-1].Value, b[len(b)-1].Value)
+			}
+		}
+	}
+}
+
+func TestBellmanFord(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddEdge("A", "T", 5)
+	g.AddEdge("A", "U", 6)
+	g.AddEdge("U", "T", -3)
+	path, err := BellmanFord(g, "A", "T")
+	if err != nil || !slices.Equal(pathKeys(path), []string{"A", "U", "T"}) || path[2].Value != 3 {
+		t.Errorf("BellmanFord = %v, %v, want A→U→T with distance 3", path, err)
+	}
+
+	g.AddEdge("T", "U", 1)
+	if _, err := BellmanFord(g, "A", "T"); !errors.Is(err, ErrNegativeCycle) {
+		t.Errorf("BellmanFord with negative cycle = %v", err)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := NewGraph[string, int](true)
+	deps := [][2]string{{"shirt", "tie"}, {"tie", "jacket"}, {"pants", "shoes"}, {"pants", "belt"}, {"belt", "jacket"}, {"socks", "shoes"}}
+	for _, d := range deps {
+		g.AddEdge(d[0], d[1], 0)
+	}
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range deps {
+		if slices.Index(order, d[0]) > slices.Index(order, d[1]) {
+			t.Errorf("%s sorted after %s in %v", d[0], d[1], order)
+		}
+	}
+
+	g.AddEdge("jacket", "shirt", 0)
+	_, err = g.TopologicalSort()
+	var cycleErr *CycleError[string]
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("TopologicalSort with cycle = %v", err)
+	}
+	c := cycleErr.Cycle
+	if len(c) < 2 || c[0] != c[len(c)-1] {
+		t.Fatalf("cycle %v does not start and end at the same vertex", c)
+	}
+	for i := 0; i+1 < len(c); i++ {
+		if !g.HasEdge(c[i], c[i+1]) {
+			t.Errorf("cycle %v uses missing edge %s→%s", c, c[i], c[i+1])
+		}
+	}
+
+	if _, err := NewGraph[int, int](false).TopologicalSort(); !errors.Is(err, ErrNotDirected) {
+		t.Errorf("TopologicalSort on undirected graph = %v", err)
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := NewGraph[int, int](true)
+	for _, e := range [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 4}, {6, 6}} {
+		g.AddEdge(e[0], e[1], 0)
+	}
+	var got [][]int
+	for _, c := range g.StronglyConnectedComponents() {
+		slices.Sort(c)
+		got = append(got, c)
+	}
+	slices.SortFunc(got, func(a, b []int) int { return a[0] - b[0] })
+	want := [][]int{{1, 2, 3}, {4, 5}, {6}}
+	if !slices.EqualFunc(got, want, slices.Equal[[]int]) {
+		t.Errorf("StronglyConnectedComponents = %v, want %v", got, want)
+	}
+}
+
+func TestMinimumSpanningTree(t *testing.T) {
+	g := NewGraph[string, int](false)
+	g.AddEdge("A", "B", 7)
+	g.AddEdge("A", "D", 5)
+	g.AddEdge("B", "C", 8)
+	g.AddEdge("B", "D", 9)
+	g.AddEdge("B", "E", 7)
+	g.AddEdge("C", "E", 5)
+	g.AddEdge("D", "E", 15)
+	g.AddEdge("X", "Y", 2)
+	tree, total, err := MinimumSpanningTree(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 两个连通分量：5个顶点需要4条边，另一个分量1条边
+	if len(tree) != 5 || total != 26 {
+		t.Errorf("MinimumSpanningTree = %v, total %d, want 5 edges with total 26", tree, total)
+	}
+	if len(g.Edges()) != 8 {
+		t.Errorf("Edges() returned %d edges, want 8", len(g.Edges()))
+	}
+	if _, _, err := MinimumSpanningTree(NewGraph[int, int](true)); !errors.Is(err, ErrNotUndirected) {
+		t.Errorf("MinimumSpanningTree on directed graph = %v", err)
+	}
+}
+
+func TestGraphTraversal(t *testing.T) {
+	g := NewGraph[int, int](false)
+	for _, e := range [][2]int{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 5}} {
+		g.AddEdge(e[0], e[1], 1)
+	}
+	g.AddVertex(6)
+	if got := slices.Collect(g.BFS(1)); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("BFS(1) = %v", got)
+	}
+	dfs := slices.Collect(g.DFS(1))
+	if len(dfs) != 5 || dfs[0] != 1 || slices.Contains(dfs, 6) {
+		t.Errorf("DFS(1) = %v", dfs)
+	}
+	// 提前退出
+	for v := range g.BFS(1) {
+		if v == 2 {
+			break
+		}
+	}
+	if got := slices.Collect(g.BFS(42)); len(got) != 0 {
+		t.Errorf("BFS from missing vertex = %v", got)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// 15. 迭代器与惰性管道
+// Go 1.23 引入了 range-over-func，函数 func(yield func(T) bool) 可以直接用于for range
+// 惰性适配器只在调用方拉取元素时才计算，不会分配中间切片
+
+// All 从栈底到栈顶遍历索引和元素
+func (s *Stack[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, v := range s.elements {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 从栈顶到栈底遍历索引和元素，即出栈顺序
+func (s *Stack[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := len(s.elements) - 1; i >= 0; i-- {
+			if !yield(i, s.elements[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Values 从栈底到栈顶遍历元素
+func (s *Stack[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s.elements {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// All 从头到尾遍历索引和元素
+func (l *LinkedList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for node := l.head; node != nil; node = node.next {
+			if !yield(i, node.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Backward 从尾到头遍历索引和元素
+func (l *LinkedList[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := l.size - 1
+		for node := l.tail; node != nil; node = node.prev {
+			if !yield(i, node.value) {
+				return
+			}
+			i--
+		}
+	}
+}
+
+// Values 从头到尾遍历元素
+func (l *LinkedList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for node := l.head; node != nil; node = node.next {
+			if !yield(node.value) {
+				return
+			}
+		}
+	}
+}
+
+// MapSeq 惰性映射：对序列中的每个元素应用fn
+func MapSeq[T, U any](seq iter.Seq[T], fn func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+// FilterSeq 惰性过滤：只保留满足predicate的元素
+func FilterSeq[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if predicate(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ReduceSeq 对序列进行归约
+func ReduceSeq[T, U any](seq iter.Seq[T], initial U, fn func(U, T) U) U {
+	result := initial
+	for v := range seq {
+		result = fn(result, v)
+	}
+	return result
+}
+
+// TakeWhile 惰性截取：遇到第一个不满足predicate的元素时停止
+func TakeWhile[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if !predicate(v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ChunkSeq 惰性分块：每次产生最多size个元素组成的切片
+// 每个块都是新分配的切片，调用方可以安全地保存
+func ChunkSeq[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("ChunkSeq: size必须大于0")
+	}
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// ZipSeq 惰性拉链：将两个序列按位置配对，较短的序列结束时停止
+func ZipSeq[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq[Pair[A, B]] {
+	return func(yield func(Pair[A, B]) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+		for va := range a {
+			vb, ok := next()
+			if !ok || !yield(Pair[A, B]{Key: va, Value: vb}) {
+				return
+			}
+		}
+	}
+}
+
+// 15.1 迭代器演示
+func demonstrateIterators() {
+	fmt.Println("\n=== 迭代器与惰性管道演示 ===")
+
+	stack := &Stack[string]{}
+	stack.Push("底")
+	stack.Push("中")
+	stack.Push("顶")
+
+	fmt.Print("栈 All: ")
+	for i, v := range stack.All() {
+		fmt.Printf("%d=%s ", i, v)
+	}
+	fmt.Print("\n栈 Backward: ")
+	for i, v := range stack.Backward() {
+		fmt.Printf("%d=%s ", i, v)
+	}
+	fmt.Println()
+
+	list := NewLinkedList[int]()
+	for i := 1; i <= 5; i++ {
+		list.Add(i)
+	}
+	fmt.Print("链表 Backward: ")
+	for i, v := range list.Backward() {
+		fmt.Printf("%d=%d ", i, v)
+	}
+	fmt.Println()
+
+	// 惰性管道：无限自然数序列，只计算需要的部分
+	naturals := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	squares := MapSeq(naturals, func(n int) int { return n * n })
+	evenSquares := FilterSeq(squares, func(n int) bool { return n%2 == 0 })
+	small := TakeWhile(evenSquares, func(n int) bool { return n < 200 })
+	fmt.Printf("小于200的偶数平方: %v\n", slices.Collect(small))
+
+	for chunk := range ChunkSeq(list.Values(), 2) {
+		fmt.Printf("分块: %v\n", chunk)
+	}
+
+	names := slices.Values([]string{"张三", "李四", "王五"})
+	for p := range ZipSeq(names, list.Values()) {
+		fmt.Printf("配对: %s -> %d\n", p.Key, p.Value)
+	}
+
+	total := ReduceSeq(list.Values(), 0, func(acc, n int) int { return acc + n })
+	fmt.Printf("链表元素总和: %d\n", total)
+}
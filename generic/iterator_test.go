@@ -0,0 +1,121 @@
+package main
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+// countingSeq 产生0..n-1，并记录被拉取了多少个元素，用于检查惰性和提前退出
+func countingSeq(n int, pulled *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			*pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestStackAndListIterators(t *testing.T) {
+	s := &Stack[string]{}
+	l := NewLinkedList[string]()
+	for _, v := range []string{"a", "b", "c"} {
+		s.Push(v)
+		l.PushBack(v)
+	}
+
+	collect2 := func(seq iter.Seq2[int, string]) ([]int, []string) {
+		var idx []int
+		var vals []string
+		for i, v := range seq {
+			idx = append(idx, i)
+			vals = append(vals, v)
+		}
+		return idx, vals
+	}
+	tests := []struct {
+		name     string
+		seq      iter.Seq2[int, string]
+		wantIdx  []int
+		wantVals []string
+	}{
+		{"Stack.All", s.All(), []int{0, 1, 2}, []string{"a", "b", "c"}},
+		{"Stack.Backward", s.Backward(), []int{2, 1, 0}, []string{"c", "b", "a"}},
+		{"LinkedList.All", l.All(), []int{0, 1, 2}, []string{"a", "b", "c"}},
+		{"LinkedList.Backward", l.Backward(), []int{2, 1, 0}, []string{"c", "b", "a"}},
+	}
+	for _, tt := range tests {
+		idx, vals := collect2(tt.seq)
+		if !slices.Equal(idx, tt.wantIdx) || !slices.Equal(vals, tt.wantVals) {
+			t.Errorf("%s = %v %v, want %v %v", tt.name, idx, vals, tt.wantIdx, tt.wantVals)
+		}
+		for range tt.seq {
+			break // 提前退出不能panic
+		}
+	}
+	if got := slices.Collect(s.Values()); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("Stack.Values() = %v", got)
+	}
+	if got := slices.Collect(l.Values()); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("LinkedList.Values() = %v", got)
+	}
+}
+
+func TestSeqAdapters(t *testing.T) {
+	double := func(v int) int { return v * 2 }
+	even := func(v int) bool { return v%2 == 0 }
+
+	if got := slices.Collect(MapSeq(slices.Values([]int{1, 2, 3}), double)); !slices.Equal(got, []int{2, 4, 6}) {
+		t.Errorf("MapSeq = %v", got)
+	}
+	if got := slices.Collect(FilterSeq(slices.Values([]int{1, 2, 3, 4}), even)); !slices.Equal(got, []int{2, 4}) {
+		t.Errorf("FilterSeq = %v", got)
+	}
+	if got := ReduceSeq(slices.Values([]int{1, 2, 3}), "", func(acc string, v int) string { return acc + string(rune('0'+v)) }); got != "123" {
+		t.Errorf("ReduceSeq = %q", got)
+	}
+	if got := slices.Collect(TakeWhile(slices.Values([]int{2, 4, 5, 6}), even)); !slices.Equal(got, []int{2, 4}) {
+		t.Errorf("TakeWhile = %v", got)
+	}
+	if got := slices.Collect(ChunkSeq(slices.Values([]int{1, 2, 3, 4, 5}), 2)); len(got) != 3 || !slices.Equal(got[2], []int{5}) {
+		t.Errorf("ChunkSeq = %v", got)
+	}
+	if got := slices.Collect(ZipSeq(slices.Values([]int{1, 2, 3}), slices.Values([]string{"a", "b"}))); len(got) != 2 || got[1] != (Pair[int, string]{2, "b"}) {
+		t.Errorf("ZipSeq = %v", got)
+	}
+}
+
+func TestSeqAdaptersAreLazy(t *testing.T) {
+	tests := []struct {
+		name string
+		take func(iter.Seq[int]) int // 从适配后的序列中取到第一个元素为止，返回它
+		want int
+		pull int // 期望从源序列拉取的元素个数
+	}{
+		{"MapSeq", func(s iter.Seq[int]) int { return first(MapSeq(s, func(v int) int { return v + 100 })) }, 100, 1},
+		{"FilterSeq", func(s iter.Seq[int]) int { return first(FilterSeq(s, func(v int) bool { return v >= 3 })) }, 3, 4},
+		{"TakeWhile", func(s iter.Seq[int]) int { return first(TakeWhile(s, func(v int) bool { return v < 5 })) }, 0, 1},
+		{"ChunkSeq", func(s iter.Seq[int]) int { return first(ChunkSeq(s, 3))[2] }, 2, 3},
+		{"ZipSeq", func(s iter.Seq[int]) int { return first(ZipSeq(s, slices.Values([]int{7}))).Key }, 0, 1},
+	}
+	for _, tt := range tests {
+		pulled := 0
+		if got := tt.take(countingSeq(1000, &pulled)); got != tt.want {
+			t.Errorf("%s first = %d, want %d", tt.name, got, tt.want)
+		}
+		if pulled != tt.pull {
+			t.Errorf("%s pulled %d elements, want %d", tt.name, pulled, tt.pull)
+		}
+	}
+}
+
+// first 返回序列的第一个元素，之后立即停止遍历
+func first[T any](seq iter.Seq[T]) T {
+	for v := range seq {
+		return v
+	}
+	var zero T
+	return zero
+}
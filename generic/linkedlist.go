@@ -0,0 +1,407 @@
+package main
+
+import "fmt"
+
+// 14. 双向链表操作
+// LinkedList 的每个节点同时保存前驱和后继指针
+// 已知节点时插入、删除都是O(1)，并且可以从尾部向前遍历
+
+// Value 返回节点保存的值
+func (n *Node[T]) Value() T {
+	return n.value
+}
+
+// SetValue 修改节点保存的值
+func (n *Node[T]) SetValue(value T) {
+	n.value = value
+}
+
+// Next 返回后继节点，没有时返回nil
+func (n *Node[T]) Next() *Node[T] {
+	return n.next
+}
+
+// Prev 返回前驱节点，没有时返回nil
+func (n *Node[T]) Prev() *Node[T] {
+	return n.prev
+}
+
+// Front 返回链表的头节点，链表为空时返回nil
+func (l *LinkedList[T]) Front() *Node[T] {
+	return l.head
+}
+
+// Back 返回链表的尾节点，链表为空时返回nil
+func (l *LinkedList[T]) Back() *Node[T] {
+	return l.tail
+}
+
+// IsEmpty 检查链表是否为空
+func (l *LinkedList[T]) IsEmpty() bool {
+	return l.size == 0
+}
+
+// PushBack 在链表末尾添加元素，返回新节点
+func (l *LinkedList[T]) PushBack(value T) *Node[T] {
+	return l.link(l.tail, &Node[T]{value: value})
+}
+
+// PushFront 在链表头部添加元素，返回新节点
+func (l *LinkedList[T]) PushFront(value T) *Node[T] {
+	return l.link(nil, &Node[T]{value: value})
+}
+
+// PopFront 移除并返回链表头部的元素
+func (l *LinkedList[T]) PopFront() (T, bool) {
+	if l.head == nil {
+		var zero T
+		return zero, false
+	}
+	return l.unlink(l.head), true
+}
+
+// PopBack 移除并返回链表末尾的元素
+func (l *LinkedList[T]) PopBack() (T, bool) {
+	if l.tail == nil {
+		var zero T
+		return zero, false
+	}
+	return l.unlink(l.tail), true
+}
+
+// InsertBefore 在mark之前插入元素，mark不属于该链表时返回nil
+func (l *LinkedList[T]) InsertBefore(value T, mark *Node[T]) *Node[T] {
+	if mark == nil || mark.list != l {
+		return nil
+	}
+	return l.link(mark.prev, &Node[T]{value: value})
+}
+
+// InsertAfter 在mark之后插入元素，mark不属于该链表时返回nil
+func (l *LinkedList[T]) InsertAfter(value T, mark *Node[T]) *Node[T] {
+	if mark == nil || mark.list != l {
+		return nil
+	}
+	return l.link(mark, &Node[T]{value: value})
+}
+
+// InsertAt 在指定索引处插入元素，索引等于Size()时追加到末尾
+func (l *LinkedList[T]) InsertAt(index int, value T) bool {
+	if index < 0 || index > l.size {
+		return false
+	}
+	if index == l.size {
+		l.PushBack(value)
+		return true
+	}
+	l.link(l.nodeAt(index).prev, &Node[T]{value: value})
+	return true
+}
+
+// RemoveAt 移除并返回指定索引处的元素
+func (l *LinkedList[T]) RemoveAt(index int) (T, bool) {
+	node := l.nodeAt(index)
+	if node == nil {
+		var zero T
+		return zero, false
+	}
+	return l.unlink(node), true
+}
+
+// Remove 从链表中移除节点，节点不属于该链表时返回false
+func (l *LinkedList[T]) Remove(node *Node[T]) (T, bool) {
+	if node == nil || node.list != l {
+		var zero T
+		return zero, false
+	}
+	return l.unlink(node), true
+}
+
+// MoveToFront 将节点移动到链表头部，不重新分配节点
+func (l *LinkedList[T]) MoveToFront(node *Node[T]) bool {
+	if node == nil || node.list != l {
+		return false
+	}
+	if node != l.head {
+		l.link(nil, l.detach(node))
+	}
+	return true
+}
+
+// MoveToBack 将节点移动到链表末尾，不重新分配节点
+func (l *LinkedList[T]) MoveToBack(node *Node[T]) bool {
+	if node == nil || node.list != l {
+		return false
+	}
+	if node != l.tail {
+		l.link(l.tail, l.detach(node))
+	}
+	return true
+}
+
+// Concat 将other的所有节点移动到链表末尾，other随后变为空链表
+// 节点不会被复制，已有的节点指针仍然有效，但归属于当前链表
+func (l *LinkedList[T]) Concat(other *LinkedList[T]) {
+	l.SpliceAfter(l.tail, other)
+}
+
+// SpliceAfter 将other的所有节点移动到mark之后，mark为nil时移动到链表头部
+// other随后变为空链表
+func (l *LinkedList[T]) SpliceAfter(mark *Node[T], other *LinkedList[T]) bool {
+	if other == nil || other == l || (mark != nil && mark.list != l) {
+		return false
+	}
+	if other.head == nil {
+		return true
+	}
+
+	// 更新节点归属，保证之后的Remove等操作能正确校验
+	for node := other.head; node != nil; node = node.next {
+		node.list = l
+	}
+
+	first, last := other.head, other.tail
+	var next *Node[T]
+	if mark == nil {
+		next = l.head
+		l.head = first
+	} else {
+		next = mark.next
+		mark.next = first
+	}
+	first.prev = mark
+	last.next = next
+	if next == nil {
+		l.tail = last
+	} else {
+		next.prev = last
+	}
+	l.size += other.size
+
+	other.head, other.tail, other.size = nil, nil, 0
+	return true
+}
+
+// Clear 清空链表
+func (l *LinkedList[T]) Clear() {
+	for node := l.head; node != nil; {
+		next := node.next
+		node.next, node.prev, node.list = nil, nil, nil
+		node = next
+	}
+	l.head, l.tail, l.size = nil, nil, 0
+}
+
+// ToSlice 按从头到尾的顺序返回所有元素
+func (l *LinkedList[T]) ToSlice() []T {
+	result := make([]T, 0, l.size)
+	for node := l.head; node != nil; node = node.next {
+		result = append(result, node.value)
+	}
+	return result
+}
+
+// nodeAt 返回指定索引的节点，从离索引更近的一端开始查找
+func (l *LinkedList[T]) nodeAt(index int) *Node[T] {
+	if index < 0 || index >= l.size {
+		return nil
+	}
+	if index < l.size/2 {
+		node := l.head
+		for i := 0; i < index; i++ {
+			node = node.next
+		}
+		return node
+	}
+	node := l.tail
+	for i := l.size - 1; i > index; i-- {
+		node = node.prev
+	}
+	return node
+}
+
+// link 将node插入到at之后，at为nil时插入到链表头部
+func (l *LinkedList[T]) link(at, node *Node[T]) *Node[T] {
+	node.list = l
+	node.prev = at
+	if at == nil {
+		node.next = l.head
+		l.head = node
+	} else {
+		node.next = at.next
+		at.next = node
+	}
+	if node.next == nil {
+		l.tail = node
+	} else {
+		node.next.prev = node
+	}
+	l.size++
+	return node
+}
+
+// unlink 将node从链表中摘除并返回它的值
+func (l *LinkedList[T]) unlink(node *Node[T]) T {
+	l.detach(node)
+	// 断开引用，避免内存泄漏以及对已删除节点的误用
+	node.next, node.prev, node.list = nil, nil, nil
+	return node.value
+}
+
+// detach 将node从相邻节点之间摘除，保留节点本身以便重新插入
+func (l *LinkedList[T]) detach(node *Node[T]) *Node[T] {
+	if node.prev == nil {
+		l.head = node.next
+	} else {
+		node.prev.next = node.next
+	}
+	if node.next == nil {
+		l.tail = node.prev
+	} else {
+		node.next.prev = node.prev
+	}
+	l.size--
+	return node
+}
+
+// Cursor 链表游标，用于在遍历的同时修改链表，不需要每次从头按索引查找
+type Cursor[T any] struct {
+	list *LinkedList[T]
+	node *Node[T]
+}
+
+// Cursor 返回指向链表头节点的游标
+func (l *LinkedList[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{list: l, node: l.head}
+}
+
+// CursorBack 返回指向链表尾节点的游标
+func (l *LinkedList[T]) CursorBack() *Cursor[T] {
+	return &Cursor[T]{list: l, node: l.tail}
+}
+
+// Valid 检查游标是否指向有效节点
+func (c *Cursor[T]) Valid() bool {
+	return c.node != nil
+}
+
+// Node 返回游标当前指向的节点
+func (c *Cursor[T]) Node() *Node[T] {
+	return c.node
+}
+
+// Value 返回游标当前指向的值
+func (c *Cursor[T]) Value() T {
+	if c.node == nil {
+		var zero T
+		return zero
+	}
+	return c.node.value
+}
+
+// Set 修改游标当前指向的值
+func (c *Cursor[T]) Set(value T) bool {
+	if c.node == nil {
+		return false
+	}
+	c.node.value = value
+	return true
+}
+
+// Next 将游标移动到后继节点
+func (c *Cursor[T]) Next() bool {
+	if c.node == nil {
+		return false
+	}
+	c.node = c.node.next
+	return c.node != nil
+}
+
+// Prev 将游标移动到前驱节点
+func (c *Cursor[T]) Prev() bool {
+	if c.node == nil {
+		return false
+	}
+	c.node = c.node.prev
+	return c.node != nil
+}
+
+// InsertBefore 在当前节点之前插入元素，游标位置不变
+// 游标无效时插入到链表末尾
+func (c *Cursor[T]) InsertBefore(value T) *Node[T] {
+	if c.node == nil {
+		return c.list.PushBack(value)
+	}
+	return c.list.InsertBefore(value, c.node)
+}
+
+// InsertAfter 在当前节点之后插入元素，游标位置不变
+// 游标无效时插入到链表头部
+func (c *Cursor[T]) InsertAfter(value T) *Node[T] {
+	if c.node == nil {
+		return c.list.PushFront(value)
+	}
+	return c.list.InsertAfter(value, c.node)
+}
+
+// Remove 移除当前节点，并将游标移动到它的后继节点
+func (c *Cursor[T]) Remove() (T, bool) {
+	if c.node == nil || c.node.list != c.list {
+		var zero T
+		return zero, false
+	}
+	next := c.node.next
+	value := c.list.unlink(c.node)
+	c.node = next
+	return value, true
+}
+
+// 14.1 双向链表演示
+func demonstrateDoublyLinkedList() {
+	fmt.Println("\n=== 双向链表演示 ===")
+
+	list := NewLinkedList[int]()
+	for i := 1; i <= 5; i++ {
+		list.PushBack(i)
+	}
+	list.PushFront(0)
+	fmt.Printf("初始链表: %v\n", list.ToSlice())
+
+	list.InsertAt(3, 100)
+	fmt.Printf("InsertAt(3, 100): %v\n", list.ToSlice())
+
+	if v, ok := list.RemoveAt(3); ok {
+		fmt.Printf("RemoveAt(3): %d, 链表: %v\n", v, list.ToSlice())
+	}
+
+	front, _ := list.PopFront()
+	back, _ := list.PopBack()
+	fmt.Printf("PopFront: %d, PopBack: %d, 链表: %v\n", front, back, list.ToSlice())
+
+	// 反向遍历
+	fmt.Print("反向遍历: ")
+	for node := list.Back(); node != nil; node = node.Prev() {
+		fmt.Printf("%d ", node.Value())
+	}
+	fmt.Println()
+
+	// 使用游标在遍历中删除偶数、在奇数后插入它的十倍
+	cursor := list.Cursor()
+	for cursor.Valid() {
+		if cursor.Value()%2 == 0 {
+			cursor.Remove()
+			continue
+		}
+		cursor.InsertAfter(cursor.Value() * 10)
+		cursor.Next()
+		cursor.Next()
+	}
+	fmt.Printf("游标修改后: %v\n", list.ToSlice())
+
+	// 拼接另一个链表
+	other := NewLinkedList[int]()
+	other.PushBack(7)
+	other.PushBack(8)
+	list.Concat(other)
+	fmt.Printf("Concat后: %v, 大小: %d, other大小: %d\n", list.ToSlice(), list.Size(), other.Size())
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkList 校验双向链接、size和反向遍历与正向遍历一致
+func checkList[T comparable](t *testing.T, l *LinkedList[T], want []T) {
+	t.Helper()
+	if got := l.ToSlice(); !slices.Equal(got, want) {
+		t.Fatalf("ToSlice() = %v, want %v", got, want)
+	}
+	if l.Size() != len(want) {
+		t.Fatalf("Size() = %d, want %d", l.Size(), len(want))
+	}
+	var back []T
+	for n := l.Back(); n != nil; n = n.Prev() {
+		if n.list != l {
+			t.Fatalf("node %v belongs to another list", n.Value())
+		}
+		back = append(back, n.Value())
+	}
+	slices.Reverse(back)
+	if !slices.Equal(back, want) {
+		t.Fatalf("backward traversal = %v, want %v", back, want)
+	}
+}
+
+func TestLinkedListRandomOps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	l := NewLinkedList[int]()
+	var ref []int
+	for i := 0; i < 5000; i++ {
+		switch r.Intn(7) {
+		case 0:
+			l.PushBack(i)
+			ref = append(ref, i)
+		case 1:
+			l.PushFront(i)
+			ref = slices.Insert(ref, 0, i)
+		case 2:
+			idx := r.Intn(len(ref) + 2)
+			ok := l.InsertAt(idx, i)
+			if ok != (idx <= len(ref)) {
+				t.Fatalf("InsertAt(%d) = %v with %d elements", idx, ok, len(ref))
+			}
+			if ok {
+				ref = slices.Insert(ref, idx, i)
+			}
+		case 3:
+			idx := r.Intn(len(ref) + 1)
+			v, ok := l.RemoveAt(idx)
+			if ok != (idx < len(ref)) || (ok && v != ref[idx]) {
+				t.Fatalf("RemoveAt(%d) = %d, %v", idx, v, ok)
+			}
+			if ok {
+				ref = slices.Delete(ref, idx, idx+1)
+			}
+		case 4:
+			v, ok := l.PopFront()
+			if ok != (len(ref) > 0) || (ok && v != ref[0]) {
+				t.Fatalf("PopFront() = %d, %v", v, ok)
+			}
+			if ok {
+				ref = ref[1:]
+			}
+		case 5:
+			v, ok := l.PopBack()
+			if ok != (len(ref) > 0) || (ok && v != ref[len(ref)-1]) {
+				t.Fatalf("PopBack() = %d, %v", v, ok)
+			}
+			if ok {
+				ref = ref[:len(ref)-1]
+			}
+		case 6:
+			if len(ref) == 0 {
+				continue
+			}
+			idx := r.Intn(len(ref))
+			c := l.Cursor()
+			for range idx {
+				c.Next()
+			}
+			v := ref[idx]
+			if r.Intn(2) == 0 {
+				l.MoveToFront(c.Node())
+				ref = slices.Insert(slices.Delete(ref, idx, idx+1), 0, v)
+			} else {
+				l.MoveToBack(c.Node())
+				ref = append(slices.Delete(ref, idx, idx+1), v)
+			}
+		}
+		checkList(t, l, ref)
+	}
+}
+
+func TestLinkedListForeignNodes(t *testing.T) {
+	a, b := NewLinkedList[int](), NewLinkedList[int]()
+	na := a.PushBack(1)
+	b.PushBack(2)
+	if b.InsertAfter(3, na) != nil || b.InsertBefore(3, na) != nil {
+		t.Fatal("inserted relative to a node of another list")
+	}
+	if _, ok := b.Remove(na); ok {
+		t.Fatal("removed a node of another list")
+	}
+	if b.MoveToFront(na) || b.MoveToBack(nil) {
+		t.Fatal("moved a foreign or nil node")
+	}
+	v, ok := a.Remove(na)
+	if !ok || v != 1 {
+		t.Fatalf("Remove() = %d, %v", v, ok)
+	}
+	if _, ok := a.Remove(na); ok {
+		t.Fatal("removed the same node twice")
+	}
+	checkList(t, a, nil)
+	checkList(t, b, []int{2})
+}
+
+func TestLinkedListSplice(t *testing.T) {
+	tests := []struct {
+		name      string
+		dst, src  []int
+		markIndex int // -1表示mark为nil
+		want      []int
+	}{
+		{"到头部", []int{1, 2}, []int{8, 9}, -1, []int{8, 9, 1, 2}},
+		{"到中间", []int{1, 2}, []int{8, 9}, 0, []int{1, 8, 9, 2}},
+		{"到末尾", []int{1, 2}, []int{8, 9}, 1, []int{1, 2, 8, 9}},
+		{"空源链表", []int{1}, nil, 0, []int{1}},
+		{"空目标链表", nil, []int{8}, -1, []int{8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst, src := NewLinkedList[int](), NewLinkedList[int]()
+			var mark *Node[int]
+			for i, v := range tt.dst {
+				n := dst.PushBack(v)
+				if i == tt.markIndex {
+					mark = n
+				}
+			}
+			var moved []*Node[int]
+			for _, v := range tt.src {
+				moved = append(moved, src.PushBack(v))
+			}
+			if !dst.SpliceAfter(mark, src) {
+				t.Fatal("SpliceAfter() = false")
+			}
+			checkList(t, dst, tt.want)
+			checkList(t, src, nil)
+			// 移动过来的节点归属于新链表，可以直接删除
+			for _, n := range moved {
+				if _, ok := dst.Remove(n); !ok {
+					t.Fatal("spliced node cannot be removed from destination")
+				}
+			}
+		})
+	}
+
+	l := NewLinkedList[int]()
+	if l.SpliceAfter(nil, l) {
+		t.Error("spliced a list into itself")
+	}
+}
+
+func TestLinkedListCursor(t *testing.T) {
+	l := NewLinkedList[int]()
+	for i := 1; i <= 6; i++ {
+		l.PushBack(i)
+	}
+	// 删除偶数，在奇数后面插入它的10倍
+	for c := l.Cursor(); c.Valid(); {
+		if c.Value()%2 == 0 {
+			c.Remove()
+			continue
+		}
+		c.InsertAfter(c.Value() * 10)
+		c.Next()
+		c.Next()
+	}
+	checkList(t, l, []int{1, 10, 3, 30, 5, 50})
+
+	c := l.CursorBack()
+	c.Set(500)
+	c.Prev()
+	c.InsertBefore(4)
+	checkList(t, l, []int{1, 10, 3, 30, 4, 5, 500})
+	for c.Valid() {
+		c.Next()
+	}
+	if c.Set(0) || c.Next() || c.Prev() {
+		t.Error("invalid cursor reported success")
+	}
+	c.InsertBefore(600)
+	checkList(t, l, []int{1, 10, 3, 30, 4, 5, 500, 600})
+
+	l.Clear()
+	checkList(t, l, nil)
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/cmplx"
+	"reflect"
+	"strings"
+)
+
+// 22. 矩阵与向量
+// 基于Numberish约束，支持整数、浮点数和复数
+// 矩阵按行优先顺序保存在一维切片中，缓存更友好
+
+var (
+	// ErrDimensionMismatch 维度不匹配
+	ErrDimensionMismatch = errors.New("维度不匹配")
+	// ErrNotSquare 不是方阵
+	ErrNotSquare = errors.New("矩阵不是方阵")
+	// ErrSingularMatrix 奇异矩阵，不可逆
+	ErrSingularMatrix = errors.New("矩阵是奇异矩阵")
+)
+
+// Field 可以做除法的数值类型约束：浮点数和复数，用于行列式和求逆
+type Field interface {
+	~float32 | ~float64 | ~complex64 | ~complex128
+}
+
+// Vector 泛型向量
+type Vector[T Numberish] []T
+
+// Add 向量逐元素相加
+func (v Vector[T]) Add(other Vector[T]) (Vector[T], error) {
+	if len(v) != len(other) {
+		return nil, ErrDimensionMismatch
+	}
+	result := make(Vector[T], len(v))
+	for i := range v {
+		result[i] = v[i] + other[i]
+	}
+	return result, nil
+}
+
+// Sub 向量逐元素相减
+func (v Vector[T]) Sub(other Vector[T]) (Vector[T], error) {
+	if len(v) != len(other) {
+		return nil, ErrDimensionMismatch
+	}
+	result := make(Vector[T], len(v))
+	for i := range v {
+		result[i] = v[i] - other[i]
+	}
+	return result, nil
+}
+
+// Scale 数乘
+func (v Vector[T]) Scale(k T) Vector[T] {
+	result := make(Vector[T], len(v))
+	for i := range v {
+		result[i] = v[i] * k
+	}
+	return result
+}
+
+// Dot 点积，复数向量不取共轭
+func (v Vector[T]) Dot(other Vector[T]) (T, error) {
+	var sum T
+	if len(v) != len(other) {
+		return sum, ErrDimensionMismatch
+	}
+	for i := range v {
+		sum += v[i] * other[i]
+	}
+	return sum, nil
+}
+
+// Matrix 泛型稠密矩阵
+type Matrix[T Numberish] struct {
+	rows, cols int
+	data       []T
+}
+
+// NewMatrix 创建rows行cols列的零矩阵
+func NewMatrix[T Numberish](rows, cols int) *Matrix[T] {
+	if rows < 0 || cols < 0 {
+		panic("NewMatrix: 行数和列数不能为负数")
+	}
+	return &Matrix[T]{rows: rows, cols: cols, data: make([]T, rows*cols)}
+}
+
+// MatrixFromRows 从二维切片创建矩阵，每一行的长度必须相同
+func MatrixFromRows[T Numberish](rows [][]T) (*Matrix[T], error) {
+	if len(rows) == 0 {
+		return NewMatrix[T](0, 0), nil
+	}
+	m := NewMatrix[T](len(rows), len(rows[0]))
+	for i, row := range rows {
+		if len(row) != m.cols {
+			return nil, fmt.Errorf("第%d行长度为%d, 期望%d: %w", i, len(row), m.cols, ErrDimensionMismatch)
+		}
+		copy(m.data[i*m.cols:], row)
+	}
+	return m, nil
+}
+
+// Identity 创建n阶单位矩阵
+func Identity[T Numberish](n int) *Matrix[T] {
+	m := NewMatrix[T](n, n)
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+// Rows 返回行数
+func (m *Matrix[T]) Rows() int {
+	return m.rows
+}
+
+// Cols 返回列数
+func (m *Matrix[T]) Cols() int {
+	return m.cols
+}
+
+// At 返回第i行第j列的元素
+func (m *Matrix[T]) At(i, j int) T {
+	return m.data[i*m.cols+j]
+}
+
+// Set 设置第i行第j列的元素
+func (m *Matrix[T]) Set(i, j int, value T) {
+	m.data[i*m.cols+j] = value
+}
+
+// Row 返回第i行的副本
+func (m *Matrix[T]) Row(i int) Vector[T] {
+	row := make(Vector[T], m.cols)
+	copy(row, m.data[i*m.cols:(i+1)*m.cols])
+	return row
+}
+
+// Clone 返回矩阵的副本
+func (m *Matrix[T]) Clone() *Matrix[T] {
+	result := NewMatrix[T](m.rows, m.cols)
+	copy(result.data, m.data)
+	return result
+}
+
+// Add 矩阵逐元素相加
+func (m *Matrix[T]) Add(other *Matrix[T]) (*Matrix[T], error) {
+	if m.rows != other.rows || m.cols != other.cols {
+		return nil, ErrDimensionMismatch
+	}
+	result := NewMatrix[T](m.rows, m.cols)
+	for i := range m.data {
+		result.data[i] = m.data[i] + other.data[i]
+	}
+	return result, nil
+}
+
+// Sub 矩阵逐元素相减
+func (m *Matrix[T]) Sub(other *Matrix[T]) (*Matrix[T], error) {
+	if m.rows != other.rows || m.cols != other.cols {
+		return nil, ErrDimensionMismatch
+	}
+	result := NewMatrix[T](m.rows, m.cols)
+	for i := range m.data {
+		result.data[i] = m.data[i] - other.data[i]
+	}
+	return result, nil
+}
+
+// Scale 数乘
+func (m *Matrix[T]) Scale(k T) *Matrix[T] {
+	result := NewMatrix[T](m.rows, m.cols)
+	for i := range m.data {
+		result.data[i] = m.data[i] * k
+	}
+	return result
+}
+
+// Transpose 转置
+func (m *Matrix[T]) Transpose() *Matrix[T] {
+	result := NewMatrix[T](m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			result.data[j*m.rows+i] = m.data[i*m.cols+j]
+		}
+	}
+	return result
+}
+
+// MulVec 矩阵乘以列向量
+func (m *Matrix[T]) MulVec(v Vector[T]) (Vector[T], error) {
+	if m.cols != len(v) {
+		return nil, ErrDimensionMismatch
+	}
+	result := make(Vector[T], m.rows)
+	for i := 0; i < m.rows; i++ {
+		var sum T
+		row := m.data[i*m.cols : (i+1)*m.cols]
+		for j, x := range row {
+			sum += x * v[j]
+		}
+		result[i] = sum
+	}
+	return result, nil
+}
+
+// 超过该规模（乘加次数）时使用分块乘法
+const blockedMulThreshold = 64 * 64 * 64
+
+// matrixBlockSize 分块大小，三个块能同时放进L1/L2缓存
+const matrixBlockSize = 64
+
+// Mul 矩阵乘法，较大的矩阵自动使用分块实现
+func (m *Matrix[T]) Mul(other *Matrix[T]) (*Matrix[T], error) {
+	if m.cols != other.rows {
+		return nil, ErrDimensionMismatch
+	}
+	result := NewMatrix[T](m.rows, other.cols)
+	if m.rows*m.cols*other.cols >= blockedMulThreshold {
+		mulBlocked(m, other, result)
+	} else {
+		mulNaive(m, other, result)
+	}
+	return result, nil
+}
+
+// mulNaive 使用i-k-j循环顺序，内层循环连续访问内存
+func mulNaive[T Numberish](a, b, c *Matrix[T]) {
+	n, p := a.cols, b.cols
+	for i := 0; i < a.rows; i++ {
+		cRow := c.data[i*p : (i+1)*p]
+		for k := 0; k < n; k++ {
+			aik := a.data[i*n+k]
+			bRow := b.data[k*p : (k+1)*p]
+			for j, bkj := range bRow {
+				cRow[j] += aik * bkj
+			}
+		}
+	}
+}
+
+// mulBlocked 分块矩阵乘法，每次只处理能放进缓存的子矩阵
+func mulBlocked[T Numberish](a, b, c *Matrix[T]) {
+	n, p := a.cols, b.cols
+	const bs = matrixBlockSize
+	for ii := 0; ii < a.rows; ii += bs {
+		iEnd := min(ii+bs, a.rows)
+		for kk := 0; kk < n; kk += bs {
+			kEnd := min(kk+bs, n)
+			for jj := 0; jj < p; jj += bs {
+				jEnd := min(jj+bs, p)
+				for i := ii; i < iEnd; i++ {
+					cRow := c.data[i*p+jj : i*p+jEnd]
+					for k := kk; k < kEnd; k++ {
+						aik := a.data[i*n+k]
+						bRow := b.data[k*p+jj : k*p+jEnd]
+						for j, bkj := range bRow {
+							cRow[j] += aik * bkj
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Equal 检查两个矩阵是否完全相等
+func (m *Matrix[T]) Equal(other *Matrix[T]) bool {
+	if m.rows != other.rows || m.cols != other.cols {
+		return false
+	}
+	for i := range m.data {
+		if m.data[i] != other.data[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String 按行输出矩阵
+func (m *Matrix[T]) String() string {
+	var sb strings.Builder
+	for i := 0; i < m.rows; i++ {
+		fmt.Fprintf(&sb, "%v\n", m.data[i*m.cols:(i+1)*m.cols])
+	}
+	return sb.String()
+}
+
+// Determinant 使用带部分主元的LU分解计算行列式
+// 主元小于所在行的舍入误差容限时视为奇异矩阵，返回0
+func Determinant[T Field](m *Matrix[T]) (T, error) {
+	if m.rows != m.cols {
+		var zero T
+		return zero, ErrNotSquare
+	}
+	lu := m.Clone()
+	n := m.rows
+	tol := singularTolerance(m)
+	var det T = 1
+	for col := 0; col < n; col++ {
+		pivot := pivotRow(lu, col)
+		if fieldAbs(lu.data[pivot*n+col]) <= tol[pivot] {
+			return 0, nil
+		}
+		if pivot != col {
+			lu.swapRows(pivot, col)
+			tol[pivot], tol[col] = tol[col], tol[pivot]
+			det = -det
+		}
+		diag := lu.data[col*n+col]
+		det *= diag
+		for r := col + 1; r < n; r++ {
+			factor := lu.data[r*n+col] / diag
+			for c := col; c < n; c++ {
+				lu.data[r*n+c] -= factor * lu.data[col*n+c]
+			}
+		}
+	}
+	return det, nil
+}
+
+// Inverse 使用高斯-约当消元求逆矩阵
+func Inverse[T Field](m *Matrix[T]) (*Matrix[T], error) {
+	if m.rows != m.cols {
+		return nil, ErrNotSquare
+	}
+	n := m.rows
+	a := m.Clone()
+	inv := Identity[T](n)
+	tol := singularTolerance(m)
+	for col := 0; col < n; col++ {
+		pivot := pivotRow(a, col)
+		if fieldAbs(a.data[pivot*n+col]) <= tol[pivot] {
+			return nil, ErrSingularMatrix
+		}
+		a.swapRows(pivot, col)
+		inv.swapRows(pivot, col)
+		tol[pivot], tol[col] = tol[col], tol[pivot]
+
+		// 将主元所在行归一化
+		diag := a.data[col*n+col]
+		for c := 0; c < n; c++ {
+			a.data[col*n+c] /= diag
+			inv.data[col*n+c] /= diag
+		}
+		// 消去其他行在该列上的元素
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			factor := a.data[r*n+col]
+			if factor == 0 {
+				continue
+			}
+			for c := 0; c < n; c++ {
+				a.data[r*n+c] -= factor * a.data[col*n+c]
+				inv.data[r*n+c] -= factor * inv.data[col*n+c]
+			}
+		}
+	}
+	return inv, nil
+}
+
+// pivotRow 在col列中从第col行开始选出绝对值最大的行，减小舍入误差
+func pivotRow[T Field](m *Matrix[T], col int) int {
+	best, bestAbs := col, fieldAbs(m.data[col*m.cols+col])
+	for r := col + 1; r < m.rows; r++ {
+		if a := fieldAbs(m.data[r*m.cols+col]); a > bestAbs {
+			best, bestAbs = r, a
+		}
+	}
+	return best
+}
+
+// singularTolerance 每一行判断主元是否为0的容限：eps * n * max_j|a_ij|
+// 浮点消元会留下量级为eps的残差，奇异矩阵的主元几乎不会恰好等于0
+// 容限按行计算而不是取整个矩阵的最大值，否则diag(1e20, 1)这样量级差异大的可逆矩阵会被误判为奇异
+// 交换行时容限跟着交换
+func singularTolerance[T Field](m *Matrix[T]) []float64 {
+	tol := make([]float64, m.rows)
+	for i := range tol {
+		var largest float64
+		for _, v := range m.data[i*m.cols : (i+1)*m.cols] {
+			largest = max(largest, fieldAbs(v))
+		}
+		tol[i] = fieldEpsilon[T]() * float64(m.rows) * largest
+	}
+	return tol
+}
+
+// fieldEpsilon 返回类型的机器精度，float32和complex64为2^-23，其余为2^-52
+func fieldEpsilon[T Field]() float64 {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Float32, reflect.Complex64:
+		return 0x1p-23
+	}
+	return 0x1p-52
+}
+
+// swapRows 交换两行
+func (m *Matrix[T]) swapRows(i, j int) {
+	if i == j {
+		return
+	}
+	for c := 0; c < m.cols; c++ {
+		m.data[i*m.cols+c], m.data[j*m.cols+c] = m.data[j*m.cols+c], m.data[i*m.cols+c]
+	}
+}
+
+// fieldAbs 返回浮点数的绝对值或复数的模
+// 类型参数不能直接调用real/imag，因此先用类型断言处理常见类型，自定义类型再借助反射
+func fieldAbs[T Field](v T) float64 {
+	switch x := any(v).(type) {
+	case float64:
+		return max(x, -x)
+	case float32:
+		return float64(max(x, -x))
+	case complex128:
+		return cmplx.Abs(x)
+	case complex64:
+		return cmplx.Abs(complex128(x))
+	}
+	rv := reflect.ValueOf(v)
+	if rv.CanComplex() {
+		return cmplx.Abs(rv.Complex())
+	}
+	f := rv.Float()
+	return max(f, -f)
+}
+
+// 22.1 矩阵与向量演示
+func demonstrateMatrix() {
+	fmt.Println("\n=== 矩阵与向量演示 ===")
+
+	v1 := Vector[float64]{1, 2, 3}
+	v2 := Vector[float64]{4, 5, 6}
+	sum, _ := v1.Add(v2)
+	dot, _ := v1.Dot(v2)
+	fmt.Printf("向量相加: %v, 点积: %.1f, 数乘: %v\n", sum, dot, v1.Scale(2))
+	if _, err := v1.Add(Vector[float64]{1}); err != nil {
+		fmt.Printf("维度不同的向量相加: %v\n", err)
+	}
+
+	a, _ := MatrixFromRows([][]int{{1, 2, 3}, {4, 5, 6}})
+	b, _ := MatrixFromRows([][]int{{7, 8}, {9, 10}, {11, 12}})
+	product, _ := a.Mul(b)
+	fmt.Printf("整数矩阵乘法:\n%v", product)
+	fmt.Printf("转置:\n%v", a.Transpose())
+
+	f, _ := MatrixFromRows([][]float64{{4, 7}, {2, 6}})
+	det, _ := Determinant(f)
+	inv, _ := Inverse(f)
+	fmt.Printf("行列式: %.1f\n逆矩阵:\n%v", det, inv)
+	check, _ := f.Mul(inv)
+	fmt.Printf("原矩阵乘以逆矩阵:\n%v", check)
+
+	singular, _ := MatrixFromRows([][]float64{{1, 2}, {2, 4}})
+	if _, err := Inverse(singular); err != nil {
+		fmt.Printf("求逆失败: %v\n", err)
+	}
+
+	c, _ := MatrixFromRows([][]complex128{{1 + 1i, 2}, {3i, 4 - 1i}})
+	cdet, _ := Determinant(c)
+	fmt.Printf("复数矩阵的行列式: %v\n", cdet)
+
+	// 大矩阵自动使用分块乘法，结果与朴素实现一致
+	n := 100
+	big := NewMatrix[float64](n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			big.Set(i, j, float64((i+j)%7))
+		}
+	}
+	blocked, _ := big.Mul(Identity[float64](n))
+	fmt.Printf("100x100分块乘法乘以单位矩阵结果不变: %v\n", blocked.Equal(big))
+}
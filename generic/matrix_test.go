@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// mustMatrix 从二维切片创建矩阵，失败时终止测试
+func mustMatrix[T Numberish](t *testing.T, rows [][]T) *Matrix[T] {
+	t.Helper()
+	m, err := MatrixFromRows(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// checkIdentity 检查m乘以inv在容限内等于单位矩阵
+func checkIdentity[T Field](t *testing.T, m, inv *Matrix[T], tol float64) {
+	t.Helper()
+	product, err := m.Mul(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < m.Rows(); i++ {
+		for j := 0; j < m.Cols(); j++ {
+			var want T
+			if i == j {
+				want = 1
+			}
+			if d := fieldAbs(product.At(i, j) - want); d > tol {
+				t.Fatalf("(m * m⁻¹)[%d][%d] = %v, want %v", i, j, product.At(i, j), want)
+			}
+		}
+	}
+}
+
+func TestMatrixMul(t *testing.T) {
+	a := mustMatrix(t, [][]int{{1, 2, 3}, {4, 5, 6}})
+	b := mustMatrix(t, [][]int{{7, 8}, {9, 10}, {11, 12}})
+	got, err := a.Mul(b)
+	if err != nil || !got.Equal(mustMatrix(t, [][]int{{58, 64}, {139, 154}})) {
+		t.Fatalf("Mul = %v, %v", got, err)
+	}
+	if _, err := a.Mul(a); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Mul with mismatched shapes = %v", err)
+	}
+	if _, err := MatrixFromRows([][]int{{1, 2}, {3}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("MatrixFromRows with ragged rows = %v", err)
+	}
+
+	// 分块乘法与朴素乘法结果一致，维度不是块大小的整数倍
+	r := rand.New(rand.NewSource(1))
+	x, y := NewMatrix[int](70, 90), NewMatrix[int](90, 130)
+	for _, m := range []*Matrix[int]{x, y} {
+		for i := range m.data {
+			m.data[i] = r.Intn(21) - 10
+		}
+	}
+	blocked, naive := NewMatrix[int](70, 130), NewMatrix[int](70, 130)
+	mulBlocked(x, y, blocked)
+	mulNaive(x, y, naive)
+	if !blocked.Equal(naive) {
+		t.Error("mulBlocked and mulNaive disagree")
+	}
+}
+
+func TestDeterminantAndInverse(t *testing.T) {
+	m := mustMatrix(t, [][]float64{{4, 7}, {2, 6}})
+	if det, err := Determinant(m); err != nil || math.Abs(det-10) > 1e-12 {
+		t.Errorf("Determinant = %v, %v, want 10", det, err)
+	}
+	inv, err := Inverse(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkIdentity(t, m, inv, 1e-12)
+
+	c := mustMatrix(t, [][]complex128{{1 + 1i, 2}, {3i, 4 - 1i}})
+	if det, _ := Determinant(c); cmplx.Abs(det-(5-3i)) > 1e-12 {
+		t.Errorf("complex Determinant = %v, want (5-3i)", det)
+	}
+	cinv, err := Inverse(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkIdentity(t, c, cinv, 1e-12)
+
+	if _, err := Inverse(NewMatrix[float64](2, 3)); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Inverse of non-square = %v", err)
+	}
+	if _, err := Determinant(NewMatrix[float64](3, 2)); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Determinant of non-square = %v", err)
+	}
+}
+
+func TestSingularMatrix(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]float64
+	}{
+		{"零矩阵", [][]float64{{0, 0}, {0, 0}}},
+		{"行成比例", [][]float64{{1, 2}, {2, 4}}},
+		// 消元后主元是6.66e-16而不是0
+		{"舍入残差", [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}},
+		{"放大后的舍入残差", [][]float64{{1e10, 2e10, 3e10}, {4e10, 5e10, 6e10}, {7e10, 8e10, 9e10}}},
+		{"列线性相关", [][]float64{{0.1, 0.2, 0.3}, {0.7, 0.3, 1.0}, {0.3, 0.9, 1.2}}},
+		{"各行量级不同", [][]float64{{1e20, 2e20, 0}, {1e-20, 2e-20, 0}, {0, 0, 1}}},
+		{"小量级行的舍入残差", [][]float64{{1e20, 0, 0, 0}, {0, 1, 2, 3}, {0, 4, 5, 6}, {0, 7, 8, 9}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustMatrix(t, tt.rows)
+			if inv, err := Inverse(m); !errors.Is(err, ErrSingularMatrix) {
+				t.Errorf("Inverse = %v, %v, want ErrSingularMatrix", inv, err)
+			}
+			if det, err := Determinant(m); err != nil || det != 0 {
+				t.Errorf("Determinant = %v, %v, want 0", det, err)
+			}
+		})
+	}
+
+	m32 := mustMatrix(t, [][]float32{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+	if _, err := Inverse(m32); !errors.Is(err, ErrSingularMatrix) {
+		t.Errorf("float32 Inverse = %v, want ErrSingularMatrix", err)
+	}
+	c := mustMatrix(t, [][]complex128{{1i, 2i}, {3 + 1i, 6 + 2i}})
+	if _, err := Inverse(c); !errors.Is(err, ErrSingularMatrix) {
+		t.Errorf("complex Inverse = %v, want ErrSingularMatrix", err)
+	}
+}
+
+func TestNearSingularMatrix(t *testing.T) {
+	// 病态但可逆的矩阵不能被误判为奇异矩阵
+	tests := []struct {
+		name string
+		rows [][]float64
+		tol  float64
+	}{
+		{"主元很小", [][]float64{{1, 1}, {1, 1 + 1e-9}}, 1e-6},
+		{"整体很小", [][]float64{{1e-20, 2e-20}, {3e-20, 4e-20}}, 1e-12},
+		{"整体很大", [][]float64{{4e20, 7e20}, {2e20, 6e20}}, 1e-12},
+		// 容限不能取整个矩阵的最大值，否则量级小的行被误判为0
+		{"对角线量级差异大", [][]float64{{1e20, 0}, {0, 1}}, 1e-12},
+		{"三种量级", [][]float64{{1e-20, 0, 0}, {0, 1e20, 0}, {0, 0, 1}}, 1e-12},
+		{"不同量级的行", [][]float64{{1e20, 1e20, 0}, {1, 2, 3}, {0, 1, 5}}, 1e-9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustMatrix(t, tt.rows)
+			inv, err := Inverse(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkIdentity(t, m, inv, tt.tol)
+			if det, _ := Determinant(m); det == 0 {
+				t.Error("Determinant = 0 for an invertible matrix")
+			}
+		})
+	}
+}
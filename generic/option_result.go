@@ -0,0 +1,318 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+// 20. Option 与 Result
+// Option[T] 表示“可能没有值”，Result[T] 表示“值或错误”
+// Go的方法不能声明额外的类型参数，因此会改变类型的 Map/AndThen 以函数形式提供
+
+// ErrNoneUnwrapped 对None调用Unwrap时panic使用的错误
+var ErrNoneUnwrapped = errors.New("对None调用了Unwrap")
+
+// Option 可选值
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+// Some 创建包含值的Option
+func Some[T any](value T) Option[T] {
+	return Option[T]{value: value, ok: true}
+}
+
+// None 创建不包含值的Option
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// OptionOf 从 (value, ok) 形式的返回值创建Option，例如map查找的结果
+func OptionOf[T any](value T, ok bool) Option[T] {
+	if !ok {
+		return None[T]()
+	}
+	return Some(value)
+}
+
+// IsSome 检查是否包含值
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone 检查是否不包含值
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// Get 以 (value, ok) 的形式返回
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// Unwrap 返回值，None时panic
+func (o Option[T]) Unwrap() T {
+	if !o.ok {
+		panic(ErrNoneUnwrapped)
+	}
+	return o.value
+}
+
+// UnwrapOr 返回值，None时返回def
+func (o Option[T]) UnwrapOr(def T) T {
+	if !o.ok {
+		return def
+	}
+	return o.value
+}
+
+// UnwrapOrElse 返回值，None时返回fn的结果
+func (o Option[T]) UnwrapOrElse(fn func() T) T {
+	if !o.ok {
+		return fn()
+	}
+	return o.value
+}
+
+// OrElse None时返回fn产生的Option
+func (o Option[T]) OrElse(fn func() Option[T]) Option[T] {
+	if !o.ok {
+		return fn()
+	}
+	return o
+}
+
+// Filter 值不满足predicate时返回None
+func (o Option[T]) Filter(predicate func(T) bool) Option[T] {
+	if o.ok && !predicate(o.value) {
+		return None[T]()
+	}
+	return o
+}
+
+// OkOr 转换为Result，None时使用err作为错误
+func (o Option[T]) OkOr(err error) Result[T] {
+	if !o.ok {
+		return Err[T](err)
+	}
+	return Ok(o.value)
+}
+
+// String 实现fmt.Stringer
+func (o Option[T]) String() string {
+	if !o.ok {
+		return "None"
+	}
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+// MapOption 对Option中的值应用fn
+func MapOption[T, U any](o Option[T], fn func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return Some(fn(o.value))
+}
+
+// AndThenOption 对Option中的值应用可能返回None的fn
+func AndThenOption[T, U any](o Option[T], fn func(T) Option[U]) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return fn(o.value)
+}
+
+// Result 值或错误
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok 创建成功的Result
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err 创建失败的Result，err不能为nil
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		panic("Err: err不能为nil")
+	}
+	return Result[T]{err: err}
+}
+
+// ResultOf 从Go惯用的 (value, error) 返回值创建Result
+func ResultOf[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	return Ok(value)
+}
+
+// IsOk 检查是否成功
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr 检查是否失败
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get 转换回Go惯用的 (value, error) 形式
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Err 返回错误，成功时返回nil
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Ok 转换为Option，丢弃错误
+func (r Result[T]) Ok() Option[T] {
+	if r.err != nil {
+		return None[T]()
+	}
+	return Some(r.value)
+}
+
+// Unwrap 返回值，失败时以错误panic
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
+
+// UnwrapOr 返回值，失败时返回def
+func (r Result[T]) UnwrapOr(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// UnwrapOrElse 返回值，失败时返回fn根据错误计算的值
+func (r Result[T]) UnwrapOrElse(fn func(error) T) T {
+	if r.err != nil {
+		return fn(r.err)
+	}
+	return r.value
+}
+
+// OrElse 失败时调用fn尝试恢复
+func (r Result[T]) OrElse(fn func(error) Result[T]) Result[T] {
+	if r.err != nil {
+		return fn(r.err)
+	}
+	return r
+}
+
+// MapErr 失败时转换错误，常用于fmt.Errorf("...: %w", err)包装
+func (r Result[T]) MapErr(fn func(error) error) Result[T] {
+	if r.err != nil {
+		return ResultOf(r.value, fn(r.err))
+	}
+	return r
+}
+
+// Is 使用errors.Is检查错误链中是否包含target
+func (r Result[T]) Is(target error) bool {
+	return r.err != nil && errors.Is(r.err, target)
+}
+
+// As 使用errors.As在错误链中查找target类型的错误
+func (r Result[T]) As(target any) bool {
+	return r.err != nil && errors.As(r.err, target)
+}
+
+// OrElseIs 只有错误链中包含target时才调用fn恢复，其他错误原样返回
+func (r Result[T]) OrElseIs(target error, fn func(error) Result[T]) Result[T] {
+	if r.Is(target) {
+		return fn(r.err)
+	}
+	return r
+}
+
+// String 实现fmt.Stringer
+func (r Result[T]) String() string {
+	if r.err != nil {
+		return fmt.Sprintf("Err(%v)", r.err)
+	}
+	return fmt.Sprintf("Ok(%v)", r.value)
+}
+
+// MapResult 对成功的值应用fn
+func MapResult[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Ok(fn(r.value))
+}
+
+// AndThenResult 对成功的值应用返回 (value, error) 的fn，可以直接串联Go惯用的函数
+func AndThenResult[T, U any](r Result[T], fn func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return ResultOf(fn(r.value))
+}
+
+// 20.1 Option与Result演示
+func demonstrateOptionResult() {
+	fmt.Println("\n=== Option与Result演示 ===")
+
+	ages := map[string]int{"张三": 30, "李四": 17}
+	adult := func(name string) Option[int] {
+		return OptionOf(ages[name], ages[name] != 0).Filter(func(age int) bool { return age >= 18 })
+	}
+	fmt.Printf("张三: %v, 李四: %v, 王五: %v\n", adult("张三"), adult("李四"), adult("王五"))
+	fmt.Printf("王五的年龄(默认0): %d\n", adult("王五").UnwrapOr(0))
+
+	label := MapOption(adult("张三"), func(age int) string { return fmt.Sprintf("%d岁", age) })
+	fmt.Printf("MapOption: %v\n", label)
+
+	// 与 (value, error) 风格的函数配合使用
+	divide := func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, errors.New("除数不能为零")
+		}
+		return a / b, nil
+	}
+
+	// 解析 -> 除法 -> 格式化，中间没有嵌套的 if err != nil
+	compute := func(input string) Result[string] {
+		parsed := ResultOf(strconv.ParseFloat(input, 64))
+		quotient := AndThenResult(parsed, func(v float64) (float64, error) { return divide(100, v) })
+		return MapResult(quotient, func(v float64) string { return fmt.Sprintf("%.2f", v) })
+	}
+	for _, input := range []string{"8", "0", "abc"} {
+		fmt.Printf("compute(%q): %v\n", input, compute(input))
+	}
+
+	// 错误链匹配：只对文件不存在的错误使用默认配置
+	readConfig := func(path string) Result[string] {
+		data := ResultOf(os.ReadFile(path)).MapErr(func(err error) error {
+			return fmt.Errorf("读取配置 %s 失败: %w", path, err)
+		})
+		return MapResult(data, func(b []byte) string { return string(b) })
+	}
+	config := readConfig("不存在的配置.json").OrElseIs(fs.ErrNotExist, func(err error) Result[string] {
+		fmt.Printf("使用默认配置, 原因: %v\n", err)
+		return Ok("{}")
+	})
+	fmt.Printf("配置内容: %v\n", config)
+
+	var pathErr *fs.PathError
+	if r := readConfig("不存在的配置.json"); r.As(&pathErr) {
+		fmt.Printf("As匹配到路径错误: 操作=%s, 路径=%s\n", pathErr.Op, pathErr.Path)
+	}
+
+	value, err := compute("4").Get()
+	fmt.Printf("转换回(value, error): %q, %v\n", value, err)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestOption(t *testing.T) {
+	m := map[string]int{"a": 1}
+	some := OptionOf(m["a"], true)
+	none := OptionOf(m["b"], false)
+
+	if !some.IsSome() || some.IsNone() || !none.IsNone() {
+		t.Fatal("IsSome/IsNone wrong")
+	}
+	if v, ok := some.Get(); v != 1 || !ok {
+		t.Errorf("Get() = %d, %v", v, ok)
+	}
+	if some.UnwrapOr(9) != 1 || none.UnwrapOr(9) != 9 || none.UnwrapOrElse(func() int { return 7 }) != 7 {
+		t.Error("UnwrapOr/UnwrapOrElse wrong")
+	}
+	if none.OrElse(func() Option[int] { return Some(3) }).Unwrap() != 3 {
+		t.Error("OrElse wrong")
+	}
+	if some.Filter(func(v int) bool { return v > 1 }).IsSome() {
+		t.Error("Filter kept a rejected value")
+	}
+	if got := MapOption(some, strconv.Itoa); got.String() != "Some(1)" {
+		t.Errorf("MapOption = %v", got)
+	}
+	half := func(v int) Option[int] {
+		if v%2 != 0 {
+			return None[int]()
+		}
+		return Some(v / 2)
+	}
+	if AndThenOption(Some(4), half).Unwrap() != 2 || AndThenOption(some, half).IsSome() || AndThenOption(none, half).IsSome() {
+		t.Error("AndThenOption wrong")
+	}
+	if none.String() != "None" {
+		t.Errorf("String() = %q", none.String())
+	}
+
+	errMissing := errors.New("missing")
+	if r := none.OkOr(errMissing); !r.Is(errMissing) {
+		t.Errorf("OkOr = %v", r)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrNoneUnwrapped {
+			t.Errorf("Unwrap on None panicked with %v", r)
+		}
+	}()
+	none.Unwrap()
+}
+
+func TestResult(t *testing.T) {
+	ok := ResultOf(strconv.Atoi("42"))
+	bad := ResultOf(strconv.Atoi("x"))
+
+	if !ok.IsOk() || !bad.IsErr() || ok.Err() != nil {
+		t.Fatal("IsOk/IsErr wrong")
+	}
+	if v, err := ok.Get(); v != 42 || err != nil {
+		t.Errorf("Get() = %d, %v", v, err)
+	}
+	if bad.UnwrapOr(-1) != -1 || bad.UnwrapOrElse(func(error) int { return -2 }) != -2 {
+		t.Error("UnwrapOr/UnwrapOrElse wrong")
+	}
+	if ok.Ok().Unwrap() != 42 || bad.Ok().IsSome() {
+		t.Error("Ok() conversion wrong")
+	}
+	if MapResult(ok, func(v int) int { return v + 1 }).Unwrap() != 43 || MapResult(bad, func(v int) int { return v }).IsOk() {
+		t.Error("MapResult wrong")
+	}
+	if AndThenResult(Ok("7"), strconv.Atoi).Unwrap() != 7 || AndThenResult(Ok("y"), strconv.Atoi).IsOk() {
+		t.Error("AndThenResult wrong")
+	}
+	if bad.OrElse(func(error) Result[int] { return Ok(0) }).Unwrap() != 0 {
+		t.Error("OrElse did not recover")
+	}
+	if ok.String() != "Ok(42)" || bad.String()[:4] != "Err(" {
+		t.Errorf("String() = %q, %q", ok.String(), bad.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Err(nil) did not panic")
+		}
+	}()
+	Err[int](nil)
+}
+
+func TestResultErrorChain(t *testing.T) {
+	_, openErr := os.Open("/definitely/not/here")
+	r := ResultOf[*os.File](nil, openErr).MapErr(func(err error) error {
+		return fmt.Errorf("读取配置: %w", err)
+	})
+	if !r.Is(fs.ErrNotExist) {
+		t.Errorf("Is(fs.ErrNotExist) = false for %v", r.Err())
+	}
+	var pathErr *fs.PathError
+	if !r.As(&pathErr) || pathErr.Path != "/definitely/not/here" {
+		t.Errorf("As(*fs.PathError) failed for %v", r.Err())
+	}
+
+	recovered := r.OrElseIs(fs.ErrNotExist, func(error) Result[*os.File] { return Ok[*os.File](nil) })
+	if !recovered.IsOk() {
+		t.Error("OrElseIs did not recover a matching error")
+	}
+	other := r.OrElseIs(fs.ErrPermission, func(error) Result[*os.File] { return Ok[*os.File](nil) })
+	if !other.IsErr() {
+		t.Error("OrElseIs recovered a non-matching error")
+	}
+	if Ok(1).Is(fs.ErrNotExist) || Ok(1).As(&pathErr) {
+		t.Error("successful Result matched an error")
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 19. 并行算法
+// 在Map/Filter/Reduce的基础上结合worker pool模式：
+// 固定数量的worker从任务通道中领取下标，结果按下标写回，因此输出顺序与输入一致
+// 任何一个任务返回错误或发生panic时，取消其余任务并返回第一个错误
+
+// PanicError worker中发生的panic被转换成的错误
+type PanicError struct {
+	Value any    // recover()得到的值
+	Stack []byte // 发生panic时的堆栈
+}
+
+// Error 实现error接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("worker发生panic: %v", e.Value)
+}
+
+// ParallelMap 并行映射，workers<=0时使用GOMAXPROCS个worker
+func ParallelMap[T, U any](ctx context.Context, slice []T, workers int, fn func(context.Context, T) (U, error)) ([]U, error) {
+	result := make([]U, len(slice))
+	err := parallelFor(ctx, len(slice), workers, func(ctx context.Context, i int) error {
+		v, err := fn(ctx, slice[i])
+		if err != nil {
+			return err
+		}
+		result[i] = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParallelFilter 并行过滤，保留满足predicate的元素并保持原有顺序
+func ParallelFilter[T any](ctx context.Context, slice []T, workers int, predicate func(context.Context, T) (bool, error)) ([]T, error) {
+	keep, err := ParallelMap(ctx, slice, workers, predicate)
+	if err != nil {
+		return nil, err
+	}
+	var result []T
+	for i, v := range slice {
+		if keep[i] {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// ParallelReduce 树形并行归约：每一轮将相邻的两个元素合并，直到只剩一个
+// fn必须满足结合律，但不要求交换律，因为合并始终按原有顺序进行
+// 空切片返回零值
+func ParallelReduce[T any](ctx context.Context, slice []T, workers int, fn func(context.Context, T, T) (T, error)) (T, error) {
+	var zero T
+	if len(slice) == 0 {
+		return zero, ctx.Err()
+	}
+
+	level := slice
+	for len(level) > 1 {
+		pairs := len(level) / 2
+		next := make([]T, pairs, pairs+1)
+		err := parallelFor(ctx, pairs, workers, func(ctx context.Context, i int) error {
+			v, err := fn(ctx, level[2*i], level[2*i+1])
+			if err != nil {
+				return err
+			}
+			next[i] = v
+			return nil
+		})
+		if err != nil {
+			return zero, err
+		}
+		// 奇数个元素时，最后一个直接进入下一轮
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		level = next
+	}
+	return level[0], ctx.Err()
+}
+
+// parallelFor 使用最多workers个goroutine对[0, n)的每个下标调用fn
+// 返回第一个错误，父context被取消时返回ctx.Err()
+func parallelFor(ctx context.Context, n, workers int, fn func(context.Context, int) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := safeCall(ctx, i, fn); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	// 分发任务，context被取消后停止分发
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	// 父context被取消时，部分下标可能没有执行
+	return context.Cause(ctx)
+}
+
+// safeCall 调用fn并将panic转换为PanicError
+func safeCall(ctx context.Context, i int, fn func(context.Context, int) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx, i)
+}
+
+// 19.1 并行算法演示
+func demonstrateParallelAlgorithms() {
+	fmt.Println("\n=== 并行算法演示 ===")
+
+	ctx := context.Background()
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	start := time.Now()
+	squared, err := ParallelMap(ctx, numbers, 4, func(ctx context.Context, n int) (int, error) {
+		time.Sleep(20 * time.Millisecond) // 模拟耗时计算
+		return n * n, nil
+	})
+	fmt.Printf("ParallelMap 平方: %v, 错误: %v, 耗时约 %v\n",
+		squared, err, time.Since(start).Round(10*time.Millisecond))
+
+	evens, _ := ParallelFilter(ctx, numbers, 4, func(ctx context.Context, n int) (bool, error) {
+		return n%2 == 0, nil
+	})
+	fmt.Printf("ParallelFilter 偶数: %v\n", evens)
+
+	// 字符串拼接满足结合律但不满足交换律，树形归约仍然保持顺序
+	words := strings.Split("Go 语言 的 并行 归约 保持 顺序", " ")
+	joined, _ := ParallelReduce(ctx, words, 3, func(ctx context.Context, a, b string) (string, error) {
+		return a + b, nil
+	})
+	fmt.Printf("ParallelReduce 拼接: %s\n", joined)
+
+	// 第一个错误会取消其余任务
+	errBadInput := errors.New("非法输入")
+	_, err = ParallelMap(ctx, numbers, 2, func(ctx context.Context, n int) (int, error) {
+		if n == 3 {
+			return 0, fmt.Errorf("处理 %d 失败: %w", n, errBadInput)
+		}
+		return n, nil
+	})
+	fmt.Printf("遇到错误提前结束: %v, errors.Is: %v\n", err, errors.Is(err, errBadInput))
+
+	// worker中的panic被转换为错误
+	_, err = ParallelMap(ctx, numbers, 2, func(ctx context.Context, n int) (int, error) {
+		if n == 5 {
+			panic("除零")
+		}
+		return 10 / n, nil
+	})
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		fmt.Printf("捕获到worker panic: %v\n", panicErr.Value)
+	}
+
+	// context超时
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancel()
+	_, err = ParallelMap(timeoutCtx, numbers, 1, func(ctx context.Context, n int) (int, error) {
+		select {
+		case <-time.After(20 * time.Millisecond):
+			return n, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	fmt.Printf("context超时: %v\n", err)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMapPreservesOrder(t *testing.T) {
+	input := make([]int, 200)
+	for i := range input {
+		input[i] = i
+	}
+	for _, workers := range []int{0, 1, 3, 500} {
+		var running, peak atomic.Int32
+		got, err := ParallelMap(context.Background(), input, workers, func(ctx context.Context, n int) (int, error) {
+			cur := running.Add(1)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			defer running.Add(-1)
+			return n * n, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range got {
+			if v != i*i {
+				t.Fatalf("workers=%d: result[%d] = %d", workers, i, v)
+			}
+		}
+		if workers > 0 && int(peak.Load()) > workers {
+			t.Errorf("workers=%d: %d tasks ran concurrently", workers, peak.Load())
+		}
+	}
+
+	got, err := ParallelMap(context.Background(), []int{}, 4, func(ctx context.Context, n int) (int, error) { return n, nil })
+	if err != nil || len(got) != 0 {
+		t.Errorf("empty input = %v, %v", got, err)
+	}
+}
+
+func TestParallelFilterAndReduce(t *testing.T) {
+	ctx := context.Background()
+	evens, err := ParallelFilter(ctx, []int{1, 2, 3, 4, 5, 6}, 2, func(ctx context.Context, n int) (bool, error) {
+		return n%2 == 0, nil
+	})
+	if err != nil || !slices.Equal(evens, []int{2, 4, 6}) {
+		t.Errorf("ParallelFilter = %v, %v", evens, err)
+	}
+
+	// 字符串拼接不满足交换律，可以检查归约顺序
+	tests := [][]string{nil, {"a"}, {"a", "b"}, {"a", "b", "c"}, strings.Split("abcdefghijk", "")}
+	for _, words := range tests {
+		got, err := ParallelReduce(ctx, words, 3, func(ctx context.Context, a, b string) (string, error) {
+			return a + b, nil
+		})
+		if want := strings.Join(words, ""); err != nil || got != want {
+			t.Errorf("ParallelReduce(%v) = %q, %v, want %q", words, got, err, want)
+		}
+	}
+}
+
+func TestParallelErrors(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad input")
+	var calls atomic.Int32
+	_, err := ParallelMap(ctx, make([]int, 1000), 2, func(ctx context.Context, _ int) (int, error) {
+		if calls.Add(1) == 3 {
+			return 0, errBad
+		}
+		return 0, nil
+	})
+	if !errors.Is(err, errBad) {
+		t.Errorf("error = %v, want %v", err, errBad)
+	}
+	if n := calls.Load(); n == 1000 {
+		t.Error("remaining tasks were not cancelled after the first error")
+	}
+
+	_, err = ParallelMap(ctx, []int{1, 0, 2}, 2, func(ctx context.Context, n int) (int, error) {
+		return 10 / n, nil
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) || len(pe.Stack) == 0 {
+		t.Errorf("panic not converted to PanicError: %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = ParallelMap(timeout, make([]int, 100), 1, func(ctx context.Context, _ int) (int, error) {
+		select {
+		case <-time.After(5 * time.Millisecond):
+			return 0, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout error = %v", err)
+	}
+}
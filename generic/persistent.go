@@ -0,0 +1,709 @@
+package main
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// 29. 持久化（不可变）数据结构
+// 每次修改都返回一个新版本，新旧版本共享未修改的部分，旧版本永远不会改变
+// 因此可以把某个版本直接交给其他goroutine读取，不需要加锁，也不需要深拷贝
+// PVector 是32叉字典树加尾部缓冲，PMap 是哈希数组映射字典树（HAMT），单次修改只复制O(log32 n)个节点
+// 批量构建时使用Transient得到可变的构建器，构建器只复制一次不属于自己的节点，之后原地修改
+
+// transientOwner 标记节点属于哪个构建器，构建器只能原地修改属于自己的节点
+// 带一个字段是为了保证每次new得到的指针都不相同（零大小类型的指针可能相等）
+type transientOwner struct{ _ byte }
+
+const (
+	pvBits  = 5
+	pvWidth = 1 << pvBits
+	pvMask  = pvWidth - 1
+)
+
+// pvNode PVector的字典树节点，内部节点使用children，叶子节点使用values
+type pvNode[T any] struct {
+	children []*pvNode[T]
+	values   []T
+	owner    *transientOwner
+}
+
+// editable 返回可以由owner修改的节点：节点已属于owner时直接返回，否则复制一份
+// owner为nil表示持久化修改，总是复制
+func (n *pvNode[T]) editable(owner *transientOwner) *pvNode[T] {
+	if owner != nil && n.owner == owner {
+		return n
+	}
+	return &pvNode[T]{children: slices.Clone(n.children), values: slices.Clone(n.values), owner: owner}
+}
+
+// PVector 持久化向量，请使用NewPVector或PVectorOf创建
+// 最后不满32个的元素放在尾部缓冲中，追加操作大多只需要复制尾部
+type PVector[T any] struct {
+	size  int
+	shift uint // 根节点所在层的位移，每层5位
+	root  *pvNode[T]
+	tail  []T
+}
+
+// NewPVector 创建空的持久化向量
+func NewPVector[T any]() *PVector[T] {
+	return &PVector[T]{shift: pvBits, root: &pvNode[T]{}}
+}
+
+// PVectorOf 用给定元素创建持久化向量
+func PVectorOf[T any](values ...T) *PVector[T] {
+	b := NewPVector[T]().Transient()
+	for _, v := range values {
+		b.Append(v)
+	}
+	return b.Persistent()
+}
+
+// Len 返回元素个数
+func (v *PVector[T]) Len() int {
+	return v.size
+}
+
+// tailOffset 返回尾部缓冲第一个元素的索引
+func (v *PVector[T]) tailOffset() int {
+	if v.size < pvWidth {
+		return 0
+	}
+	return ((v.size - 1) >> pvBits) << pvBits
+}
+
+// leafFor 返回包含第i个元素的叶子数组
+func (v *PVector[T]) leafFor(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+	node := v.root
+	for level := v.shift; level > 0; level -= pvBits {
+		node = node.children[(i>>level)&pvMask]
+	}
+	return node.values
+}
+
+// Get 返回第index个元素
+func (v *PVector[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= v.size {
+		var zero T
+		return zero, false
+	}
+	return v.leafFor(index)[index&pvMask], true
+}
+
+// Set 返回第index个元素被替换后的新版本，索引越界时返回false
+func (v *PVector[T]) Set(index int, value T) (*PVector[T], bool) {
+	if index < 0 || index >= v.size {
+		return v, false
+	}
+	nv := *v
+	nv.set(nil, index, value)
+	return &nv, true
+}
+
+// Append 返回在末尾追加元素后的新版本
+func (v *PVector[T]) Append(value T) *PVector[T] {
+	nv := *v
+	nv.append(nil, value)
+	return &nv
+}
+
+// Pop 返回移除最后一个元素后的新版本和被移除的元素
+func (v *PVector[T]) Pop() (*PVector[T], T, bool) {
+	if v.size == 0 {
+		var zero T
+		return v, zero, false
+	}
+	last := v.tail[len(v.tail)-1]
+	nv := *v
+	nv.pop()
+	return &nv, last, true
+}
+
+// set 修改第index个元素，owner为nil时沿路径复制节点
+func (v *PVector[T]) set(owner *transientOwner, index int, value T) {
+	if index >= v.tailOffset() {
+		if owner == nil {
+			v.tail = slices.Clone(v.tail)
+		}
+		v.tail[index&pvMask] = value
+		return
+	}
+	v.root = v.root.editable(owner)
+	node := v.root
+	for level := v.shift; level > 0; level -= pvBits {
+		i := (index >> level) & pvMask
+		node.children[i] = node.children[i].editable(owner)
+		node = node.children[i]
+	}
+	node.values[index&pvMask] = value
+}
+
+// append 追加元素，尾部满32个时整体下放到字典树中
+func (v *PVector[T]) append(owner *transientOwner, value T) {
+	if v.size-v.tailOffset() < pvWidth {
+		if owner == nil {
+			// 持久化追加时复制尾部，旧版本仍然看到原来的尾部
+			tail := make([]T, len(v.tail)+1)
+			copy(tail, v.tail)
+			tail[len(v.tail)] = value
+			v.tail = tail
+		} else {
+			v.tail = append(v.tail, value)
+		}
+		v.size++
+		return
+	}
+
+	leaf := &pvNode[T]{values: v.tail, owner: owner}
+	if (v.size >> pvBits) > (1 << v.shift) {
+		// 根节点已满，树长高一层
+		v.root = &pvNode[T]{children: []*pvNode[T]{v.root, newPVPath(owner, v.shift, leaf)}, owner: owner}
+		v.shift += pvBits
+	} else {
+		v.root = v.pushTail(owner, v.shift, v.root, leaf)
+	}
+	if owner == nil {
+		v.tail = []T{value}
+	} else {
+		v.tail = make([]T, 1, pvWidth)
+		v.tail[0] = value
+	}
+	v.size++
+}
+
+// pushTail 将满的尾部作为叶子插入到level层的parent之下
+func (v *PVector[T]) pushTail(owner *transientOwner, level uint, parent, leaf *pvNode[T]) *pvNode[T] {
+	n := parent.editable(owner)
+	i := ((v.size - 1) >> level) & pvMask
+	if level == pvBits {
+		n.children = append(n.children, leaf)
+		return n
+	}
+	if i < len(n.children) {
+		n.children[i] = v.pushTail(owner, level-pvBits, n.children[i], leaf)
+	} else {
+		n.children = append(n.children, newPVPath(owner, level-pvBits, leaf))
+	}
+	return n
+}
+
+// newPVPath 创建从level层到叶子的一条单链路径
+func newPVPath[T any](owner *transientOwner, level uint, leaf *pvNode[T]) *pvNode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &pvNode[T]{children: []*pvNode[T]{newPVPath(owner, level-pvBits, leaf)}, owner: owner}
+}
+
+// pop 移除最后一个元素，尾部为空时把字典树最右的叶子取出作为新的尾部
+func (v *PVector[T]) pop() {
+	if v.size == 1 {
+		*v = *NewPVector[T]()
+		return
+	}
+	if v.size-v.tailOffset() > 1 {
+		v.tail = v.tail[: len(v.tail)-1 : len(v.tail)-1]
+		v.size--
+		return
+	}
+	v.tail = v.leafFor(v.size - 2)
+	root := v.popTail(v.shift, v.root)
+	if root == nil {
+		root = &pvNode[T]{}
+	}
+	if v.shift > pvBits && len(root.children) == 1 {
+		root = root.children[0]
+		v.shift -= pvBits
+	}
+	v.root = root
+	v.size--
+}
+
+// popTail 移除最右的叶子，节点变空时返回nil
+func (v *PVector[T]) popTail(level uint, node *pvNode[T]) *pvNode[T] {
+	i := ((v.size - 2) >> level) & pvMask
+	if level > pvBits {
+		child := v.popTail(level-pvBits, node.children[i])
+		if child == nil && i == 0 {
+			return nil
+		}
+		n := node.editable(nil)
+		if child == nil {
+			n.children = n.children[:i]
+		} else {
+			n.children[i] = child
+		}
+		return n
+	}
+	if i == 0 {
+		return nil
+	}
+	n := node.editable(nil)
+	n.children = n.children[:i]
+	return n
+}
+
+// All 按顺序遍历索引和元素
+func (v *PVector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for base := 0; base < v.size; base += pvWidth {
+			for j, value := range v.leafFor(base) {
+				if !yield(base+j, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Values 按顺序遍历元素
+func (v *PVector[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range v.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 转换为切片
+func (v *PVector[T]) ToSlice() []T {
+	result := make([]T, 0, v.size)
+	for _, value := range v.All() {
+		result = append(result, value)
+	}
+	return result
+}
+
+// Transient 返回基于当前版本的构建器，构建器的修改不会影响当前版本
+func (v *PVector[T]) Transient() *PVectorBuilder[T] {
+	vec := *v
+	tail := make([]T, len(v.tail), pvWidth)
+	copy(tail, v.tail)
+	vec.tail = tail
+	return &PVectorBuilder[T]{vec: vec, owner: &transientOwner{}}
+}
+
+// PVectorBuilder PVector的可变构建器，不是并发安全的，调用Persistent后不能再使用
+type PVectorBuilder[T any] struct {
+	vec   PVector[T]
+	owner *transientOwner
+}
+
+// ensureActive 检查构建器是否还能使用
+func (b *PVectorBuilder[T]) ensureActive() {
+	if b.owner == nil {
+		panic("PVectorBuilder: 调用Persistent之后不能再修改")
+	}
+}
+
+// Len 返回元素个数
+func (b *PVectorBuilder[T]) Len() int {
+	return b.vec.size
+}
+
+// Get 返回第index个元素
+func (b *PVectorBuilder[T]) Get(index int) (T, bool) {
+	return b.vec.Get(index)
+}
+
+// Append 原地追加元素
+func (b *PVectorBuilder[T]) Append(value T) *PVectorBuilder[T] {
+	b.ensureActive()
+	b.vec.append(b.owner, value)
+	return b
+}
+
+// Set 原地修改第index个元素，索引越界时返回false
+func (b *PVectorBuilder[T]) Set(index int, value T) bool {
+	b.ensureActive()
+	if index < 0 || index >= b.vec.size {
+		return false
+	}
+	b.vec.set(b.owner, index, value)
+	return true
+}
+
+// Persistent 结束构建并返回不可变的版本
+func (b *PVectorBuilder[T]) Persistent() *PVector[T] {
+	b.ensureActive()
+	b.owner = nil
+	vec := b.vec
+	vec.tail = vec.tail[:len(vec.tail):len(vec.tail)]
+	return &vec
+}
+
+// pmapSeed PMap使用的哈希种子
+var pmapSeed = maphash.MakeSeed()
+
+// hamtNode HAMT节点：bitmap的第i位表示哈希在该层的5位等于i的条目是否存在，entries紧凑存放
+// 64位哈希用完后（shift>=64）仍冲突的键存放在collisions中
+type hamtNode[K comparable, V any] struct {
+	bitmap     uint32
+	entries    []hamtEntry[K, V]
+	collisions []Pair[K, V]
+	owner      *transientOwner
+}
+
+// hamtEntry 节点中的条目：node不为nil时是子节点，否则是键值对
+type hamtEntry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	node  *hamtNode[K, V]
+}
+
+// editable 返回可以由owner修改的节点，规则与pvNode相同
+func (n *hamtNode[K, V]) editable(owner *transientOwner) *hamtNode[K, V] {
+	if n == nil {
+		return &hamtNode[K, V]{owner: owner}
+	}
+	if owner != nil && n.owner == owner {
+		return n
+	}
+	return &hamtNode[K, V]{
+		bitmap:     n.bitmap,
+		entries:    slices.Clone(n.entries),
+		collisions: slices.Clone(n.collisions),
+		owner:      owner,
+	}
+}
+
+// isEmpty 判断节点是否没有任何条目
+func (n *hamtNode[K, V]) isEmpty() bool {
+	return len(n.entries) == 0 && len(n.collisions) == 0
+}
+
+// get 查找键
+func (n *hamtNode[K, V]) get(shift uint, hash uint64, key K) (V, bool) {
+	for n != nil {
+		if shift >= 64 {
+			for _, p := range n.collisions {
+				if p.Key == key {
+					return p.Value, true
+				}
+			}
+			break
+		}
+		bit := uint32(1) << ((hash >> shift) & pvMask)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		e := &n.entries[bits.OnesCount32(n.bitmap&(bit-1))]
+		if e.node == nil {
+			if e.key == key {
+				return e.value, true
+			}
+			break
+		}
+		n, shift = e.node, shift+pvBits
+	}
+	var zero V
+	return zero, false
+}
+
+// insert 插入或更新键值，返回修改后的节点以及是否为新键
+func (n *hamtNode[K, V]) insert(owner *transientOwner, shift uint, hash uint64, key K, value V) (*hamtNode[K, V], bool) {
+	if shift >= 64 {
+		if n != nil {
+			if i := slices.IndexFunc(n.collisions, func(p Pair[K, V]) bool { return p.Key == key }); i >= 0 {
+				m := n.editable(owner)
+				m.collisions[i].Value = value
+				return m, false
+			}
+		}
+		m := n.editable(owner)
+		m.collisions = append(m.collisions, Pair[K, V]{Key: key, Value: value})
+		return m, true
+	}
+
+	bit := uint32(1) << ((hash >> shift) & pvMask)
+	var i int
+	if n != nil {
+		i = bits.OnesCount32(n.bitmap & (bit - 1))
+	}
+	if n == nil || n.bitmap&bit == 0 {
+		m := n.editable(owner)
+		m.entries = slices.Insert(m.entries, i, hamtEntry[K, V]{hash: hash, key: key, value: value})
+		m.bitmap |= bit
+		return m, true
+	}
+
+	e := n.entries[i]
+	switch {
+	case e.node != nil:
+		child, added := e.node.insert(owner, shift+pvBits, hash, key, value)
+		m := n.editable(owner)
+		m.entries[i].node = child
+		return m, added
+	case e.key == key:
+		m := n.editable(owner)
+		m.entries[i].value = value
+		return m, false
+	default:
+		// 两个键在这一层冲突，下推到新的子节点
+		var child *hamtNode[K, V]
+		child, _ = child.insert(owner, shift+pvBits, e.hash, e.key, e.value)
+		child, _ = child.insert(owner, shift+pvBits, hash, key, value)
+		m := n.editable(owner)
+		m.entries[i] = hamtEntry[K, V]{node: child}
+		return m, true
+	}
+}
+
+// delete 删除键，返回修改后的节点以及键是否存在
+// 子节点只剩一个键值对时把它提升到当前节点，保持树的紧凑
+func (n *hamtNode[K, V]) delete(owner *transientOwner, shift uint, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	if shift >= 64 {
+		i := slices.IndexFunc(n.collisions, func(p Pair[K, V]) bool { return p.Key == key })
+		if i < 0 {
+			return n, false
+		}
+		m := n.editable(owner)
+		m.collisions = slices.Delete(m.collisions, i, i+1)
+		return m, true
+	}
+
+	bit := uint32(1) << ((hash >> shift) & pvMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := bits.OnesCount32(n.bitmap & (bit - 1))
+	e := n.entries[i]
+	if e.node == nil {
+		if e.key != key {
+			return n, false
+		}
+		m := n.editable(owner)
+		m.entries = slices.Delete(m.entries, i, i+1)
+		m.bitmap &^= bit
+		return m, true
+	}
+
+	child, removed := e.node.delete(owner, shift+pvBits, hash, key)
+	if !removed {
+		return n, false
+	}
+	m := n.editable(owner)
+	switch {
+	case child.isEmpty():
+		m.entries = slices.Delete(m.entries, i, i+1)
+		m.bitmap &^= bit
+	case len(child.entries) == 1 && child.entries[0].node == nil:
+		m.entries[i] = child.entries[0]
+	case len(child.collisions) == 1:
+		p := child.collisions[0]
+		m.entries[i] = hamtEntry[K, V]{hash: hash, key: p.Key, value: p.Value}
+	default:
+		m.entries[i].node = child
+	}
+	return m, true
+}
+
+// walk 遍历子树中的所有键值对
+func (n *hamtNode[K, V]) walk(yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, p := range n.collisions {
+		if !yield(p.Key, p.Value) {
+			return false
+		}
+	}
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.walk(yield) {
+				return false
+			}
+		} else if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// PMap 持久化哈希映射，零值是可以直接使用的空映射
+type PMap[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	size int
+}
+
+// NewPMap 创建空的持久化映射
+func NewPMap[K comparable, V any]() *PMap[K, V] {
+	return &PMap[K, V]{}
+}
+
+// Len 返回键的数量
+func (m *PMap[K, V]) Len() int {
+	return m.size
+}
+
+// Get 查找键
+func (m *PMap[K, V]) Get(key K) (V, bool) {
+	return m.root.get(0, maphash.Comparable(pmapSeed, key), key)
+}
+
+// Contains 判断键是否存在
+func (m *PMap[K, V]) Contains(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Set 返回设置键值后的新版本
+func (m *PMap[K, V]) Set(key K, value V) *PMap[K, V] {
+	root, added := m.root.insert(nil, 0, maphash.Comparable(pmapSeed, key), key, value)
+	nm := &PMap[K, V]{root: root, size: m.size}
+	if added {
+		nm.size++
+	}
+	return nm
+}
+
+// Delete 返回删除键后的新版本，键不存在时返回m本身
+func (m *PMap[K, V]) Delete(key K) *PMap[K, V] {
+	root, removed := m.root.delete(nil, 0, maphash.Comparable(pmapSeed, key), key)
+	if !removed {
+		return m
+	}
+	return &PMap[K, V]{root: root, size: m.size - 1}
+}
+
+// All 遍历所有键值对，顺序由哈希决定，但同一个版本的顺序是固定的
+func (m *PMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.walk(yield)
+	}
+}
+
+// Transient 返回基于当前版本的构建器，构建器的修改不会影响当前版本
+func (m *PMap[K, V]) Transient() *PMapBuilder[K, V] {
+	return &PMapBuilder[K, V]{m: *m, owner: &transientOwner{}}
+}
+
+// PMapBuilder PMap的可变构建器，不是并发安全的，调用Persistent后不能再使用
+type PMapBuilder[K comparable, V any] struct {
+	m     PMap[K, V]
+	owner *transientOwner
+}
+
+// ensureActive 检查构建器是否还能使用
+func (b *PMapBuilder[K, V]) ensureActive() {
+	if b.owner == nil {
+		panic("PMapBuilder: 调用Persistent之后不能再修改")
+	}
+}
+
+// Len 返回键的数量
+func (b *PMapBuilder[K, V]) Len() int {
+	return b.m.size
+}
+
+// Get 查找键
+func (b *PMapBuilder[K, V]) Get(key K) (V, bool) {
+	return b.m.Get(key)
+}
+
+// Set 原地设置键值
+func (b *PMapBuilder[K, V]) Set(key K, value V) *PMapBuilder[K, V] {
+	b.ensureActive()
+	root, added := b.m.root.insert(b.owner, 0, maphash.Comparable(pmapSeed, key), key, value)
+	b.m.root = root
+	if added {
+		b.m.size++
+	}
+	return b
+}
+
+// Delete 原地删除键，返回键是否存在
+func (b *PMapBuilder[K, V]) Delete(key K) bool {
+	b.ensureActive()
+	root, removed := b.m.root.delete(b.owner, 0, maphash.Comparable(pmapSeed, key), key)
+	b.m.root = root
+	if removed {
+		b.m.size--
+	}
+	return removed
+}
+
+// Persistent 结束构建并返回不可变的版本
+func (b *PMapBuilder[K, V]) Persistent() *PMap[K, V] {
+	b.ensureActive()
+	b.owner = nil
+	m := b.m
+	return &m
+}
+
+// 29.1 持久化数据结构演示
+func demonstratePersistent() {
+	fmt.Println("\n=== 持久化数据结构演示 ===")
+
+	// 结构共享：修改返回新版本，旧版本保持不变
+	v1 := PVectorOf(1, 2, 3, 4, 5)
+	v2 := v1.Append(6)
+	v3, _ := v2.Set(0, 100)
+	v4, last, _ := v3.Pop()
+	fmt.Printf("v1=%v v2=%v v3=%v\n", v1.ToSlice(), v2.ToSlice(), v3.ToSlice())
+	fmt.Printf("v3.Pop() -> %v, 弹出%d\n", v4.ToSlice(), last)
+
+	// 构建器批量加载，之后每次修改只复制一条路径
+	b := NewPVector[int]().Transient()
+	for i := 0; i < 100_000; i++ {
+		b.Append(i * i)
+	}
+	big := b.Persistent()
+	changed, _ := big.Set(50_000, -1)
+	before, _ := big.Get(50_000)
+	after, _ := changed.Get(50_000)
+	fmt.Printf("10万个元素: 原版本[50000]=%d, 新版本[50000]=%d, 长度%d\n", before, after, changed.Len())
+
+	// PMap：配置快照
+	cfg := NewPMap[string, string]().Transient().
+		Set("env", "prod").
+		Set("region", "cn-north").
+		Set("replicas", "3").
+		Persistent()
+	next := cfg.Set("replicas", "5").Delete("region")
+	replicas, _ := cfg.Get("replicas")
+	newReplicas, _ := next.Get("replicas")
+	fmt.Printf("旧配置: %d项 replicas=%s, 新配置: %d项 replicas=%s, region存在=%v\n",
+		cfg.Len(), replicas, next.Len(), newReplicas, next.Contains("region"))
+
+	// 无锁快照：写者发布新版本，读者随时读取某个完整的版本
+	var current atomic.Pointer[PMap[string, int]]
+	current.Store(NewPMap[string, int]())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			snapshot := current.Load()
+			// 同一个快照中两个计数器始终相等
+			requests, _ := snapshot.Get("requests")
+			responses, _ := snapshot.Get("responses")
+			if requests != responses {
+				fmt.Println("读到了不一致的快照")
+			}
+		}
+	}()
+	for i := 1; i <= 1000; i++ {
+		next := current.Load().Set("requests", i).Set("responses", i)
+		current.Store(next)
+	}
+	wg.Wait()
+	final := current.Load()
+	requests, _ := final.Get("requests")
+	fmt.Printf("最终快照: requests=%d, 共%d个键\n", requests, final.Len())
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestPVectorVersions(t *testing.T) {
+	// 随机选取一个历史版本进行修改，所有保留的版本都必须与各自的参照切片一致
+	type version struct {
+		v   *PVector[int]
+		ref []int
+	}
+	r := rand.New(rand.NewSource(1))
+	versions := []version{{NewPVector[int](), nil}}
+	for i := 0; i < 5000; i++ {
+		base := versions[r.Intn(len(versions))]
+		v, ref := base.v, slices.Clone(base.ref)
+		switch r.Intn(5) {
+		case 0, 1:
+			// 追加的数量跨越尾部和多层字典树的边界
+			for j := range r.Intn(3) * r.Intn(700) {
+				v = v.Append(i*1000 + j)
+				ref = append(ref, i*1000+j)
+			}
+		case 2:
+			if len(ref) == 0 {
+				continue
+			}
+			j := r.Intn(len(ref))
+			v, _ = v.Set(j, -i)
+			ref[j] = -i
+		case 3:
+			for range r.Intn(100) {
+				nv, x, ok := v.Pop()
+				if ok != (len(ref) > 0) || ok && x != ref[len(ref)-1] {
+					t.Fatalf("Pop() = %d, %v", x, ok)
+				}
+				if !ok {
+					break
+				}
+				v, ref = nv, ref[:len(ref)-1]
+			}
+		case 4:
+			b := v.Transient()
+			for j := range r.Intn(1500) {
+				if len(ref) > 0 && r.Intn(3) == 0 {
+					k := r.Intn(len(ref))
+					b.Set(k, 7*j)
+					ref[k] = 7 * j
+				} else {
+					b.Append(j)
+					ref = append(ref, j)
+				}
+			}
+			v = b.Persistent()
+		}
+		versions = append(versions, version{v, ref})
+		if len(versions) > 30 {
+			versions = versions[1:]
+		}
+		if i%50 == 0 {
+			for _, ver := range versions {
+				if ver.v.Len() != len(ver.ref) || !slices.Equal(ver.v.ToSlice(), ver.ref) {
+					t.Fatalf("after %d operations a version has %d elements, want %v", i, ver.v.Len(), len(ver.ref))
+				}
+			}
+		}
+	}
+	for _, ver := range versions {
+		for j, want := range ver.ref {
+			if got, ok := ver.v.Get(j); !ok || got != want {
+				t.Fatalf("Get(%d) = %d, %v, want %d", j, got, ok, want)
+			}
+		}
+	}
+}
+
+func TestPVectorBounds(t *testing.T) {
+	v := PVectorOf(1, 2, 3)
+	if _, ok := v.Get(3); ok {
+		t.Error("Get(3) succeeded on a vector of length 3")
+	}
+	if nv, ok := v.Set(-1, 0); ok || nv != v {
+		t.Error("Set(-1) succeeded")
+	}
+	empty := NewPVector[int]()
+	if nv, _, ok := empty.Pop(); ok || nv != empty {
+		t.Error("Pop() on empty vector succeeded")
+	}
+	var got []int
+	for _, x := range v.All() {
+		got = append(got, x)
+		break
+	}
+	if !slices.Equal(got, []int{1}) {
+		t.Errorf("All() with break = %v", got)
+	}
+
+	b := v.Transient()
+	b.Append(4)
+	if v.Len() != 3 || b.Len() != 4 {
+		t.Errorf("builder modified the original: %d, %d", v.Len(), b.Len())
+	}
+	b.Persistent()
+	defer func() {
+		if recover() == nil {
+			t.Error("Append after Persistent did not panic")
+		}
+	}()
+	b.Append(5)
+}
+
+func TestPMapVersions(t *testing.T) {
+	type version struct {
+		m   *PMap[int, int]
+		ref map[int]int
+	}
+	r := rand.New(rand.NewSource(1))
+	versions := []version{{NewPMap[int, int](), map[int]int{}}}
+	for i := 0; i < 5000; i++ {
+		base := versions[r.Intn(len(versions))]
+		m, ref := base.m, maps.Clone(base.ref)
+		if r.Intn(5) == 0 {
+			b := m.Transient()
+			for j := range 200 {
+				k := r.Intn(2000)
+				if r.Intn(3) == 0 {
+					_, exists := ref[k]
+					if b.Delete(k) != exists {
+						t.Fatalf("builder Delete(%d) = %v, want %v", k, !exists, exists)
+					}
+					delete(ref, k)
+				} else {
+					b.Set(k, j)
+					ref[k] = j
+				}
+			}
+			m = b.Persistent()
+		} else {
+			for range 10 {
+				k := r.Intn(2000)
+				if r.Intn(2) == 0 {
+					m = m.Delete(k)
+					delete(ref, k)
+				} else {
+					m = m.Set(k, i)
+					ref[k] = i
+				}
+			}
+		}
+		versions = append(versions, version{m, ref})
+		if len(versions) > 30 {
+			versions = versions[1:]
+		}
+		if i%100 == 0 {
+			for _, ver := range versions {
+				got := maps.Collect(ver.m.All())
+				if ver.m.Len() != len(ver.ref) || !maps.Equal(got, ver.ref) {
+					t.Fatalf("after %d operations a version has %d keys, want %d", i, ver.m.Len(), len(ver.ref))
+				}
+			}
+		}
+	}
+
+	var zero PMap[string, int]
+	if zero.Contains("a") || zero.Len() != 0 {
+		t.Error("zero PMap is not empty")
+	}
+	if zero.Delete("a") != &zero {
+		t.Error("Delete of a missing key returned a new version")
+	}
+}
+
+func TestHAMTCollisions(t *testing.T) {
+	// 直接使用相同的哈希插入，覆盖64位哈希用完之后的冲突链
+	var n *hamtNode[int, int]
+	for k := 0; k < 50; k++ {
+		n, _ = n.insert(nil, 0, 42, k, k)
+	}
+	for k := 0; k < 50; k++ {
+		if v, ok := n.get(0, 42, k); !ok || v != k {
+			t.Fatalf("get(%d) = %d, %v", k, v, ok)
+		}
+	}
+	for k := 0; k < 49; k++ {
+		var ok bool
+		if n, ok = n.delete(nil, 0, 42, k); !ok {
+			t.Fatalf("delete(%d) failed", k)
+		}
+	}
+	if v, ok := n.get(0, 42, 49); !ok || v != 49 {
+		t.Fatalf("get(49) = %d, %v", v, ok)
+	}
+	count := 0
+	n.walk(func(int, int) bool { count++; return true })
+	if count != 1 {
+		t.Errorf("walk visited %d entries, want 1", count)
+	}
+}
+
+func TestPersistentConcurrentReaders(t *testing.T) {
+	// 旧版本可以在其他goroutine中无锁读取，同时创建新版本，配合go test -race运行
+	values := make([]int, 1000)
+	m := NewPMap[int, int]().Transient()
+	for i := range values {
+		values[i] = i
+		m.Set(i, i)
+	}
+	v := PVectorOf(values...)
+	pm := m.Persistent()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				if x, _ := v.Get(i); x != i {
+					t.Errorf("Get(%d) = %d", i, x)
+					return
+				}
+				if x, _ := pm.Get(i); x != i {
+					t.Errorf("PMap Get(%d) = %d", i, x)
+					return
+				}
+			}
+		}()
+	}
+	nv, npm := v, pm
+	for i := range 1000 {
+		nv, _ = nv.Set(i, -i)
+		npm = npm.Set(i, -i)
+	}
+	wg.Wait()
+	if x, _ := nv.Get(999); x != -999 {
+		t.Errorf("new version Get(999) = %d", x)
+	}
+	if x, _ := npm.Get(999); x != -999 {
+		t.Errorf("new PMap version Get(999) = %d", x)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// 16. 优先队列
+// 基于二叉堆实现，Push/Pop/Update 的时间复杂度都是O(log n)
+// 元素可以实现 Comparable[T] 接口，也可以显式传入比较函数
+
+// HeapMode 堆的排序模式
+type HeapMode int
+
+const (
+	MinHeap HeapMode = iota // 最小堆：Pop返回最小的元素
+	MaxHeap                 // 最大堆：Pop返回最大的元素
+)
+
+// PQItem 优先队列中元素的句柄，用于之后修改或删除该元素
+type PQItem[T any] struct {
+	value T
+	index int // 在堆中的位置，元素出队后为-1
+}
+
+// Value 返回句柄对应的元素
+func (it *PQItem[T]) Value() T {
+	return it.value
+}
+
+// PriorityQueue 泛型优先队列
+type PriorityQueue[T any] struct {
+	items []*PQItem[T]
+	less  func(a, b T) bool
+}
+
+// NewPriorityQueue 创建元素实现了Comparable接口的优先队列
+func NewPriorityQueue[T Comparable[T]](mode HeapMode) *PriorityQueue[T] {
+	return NewPriorityQueueFunc(func(a, b T) bool {
+		return a.Compare(b) < 0
+	}, mode)
+}
+
+// NewPriorityQueueFunc 使用比较函数创建优先队列，less(a, b)表示a小于b
+func NewPriorityQueueFunc[T any](less func(a, b T) bool, mode HeapMode) *PriorityQueue[T] {
+	if mode == MaxHeap {
+		return &PriorityQueue[T]{less: func(a, b T) bool { return less(b, a) }}
+	}
+	return &PriorityQueue[T]{less: less}
+}
+
+// Len 返回队列中元素的数量
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// IsEmpty 检查队列是否为空
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return len(pq.items) == 0
+}
+
+// Push 添加元素，返回可用于Update和Remove的句柄
+func (pq *PriorityQueue[T]) Push(value T) *PQItem[T] {
+	item := &PQItem[T]{value: value, index: len(pq.items)}
+	pq.items = append(pq.items, item)
+	pq.up(item.index)
+	return item
+}
+
+// Peek 返回优先级最高的元素但不出队
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.items[0].value, true
+}
+
+// Pop 移除并返回优先级最高的元素
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.removeAt(0), true
+}
+
+// Update 修改句柄对应的元素并恢复堆的性质，句柄已出队时返回false
+func (pq *PriorityQueue[T]) Update(item *PQItem[T], value T) bool {
+	if !pq.owns(item) {
+		return false
+	}
+	item.value = value
+	pq.fix(item.index)
+	return true
+}
+
+// Remove 移除句柄对应的元素，句柄已出队时返回false
+func (pq *PriorityQueue[T]) Remove(item *PQItem[T]) (T, bool) {
+	if !pq.owns(item) {
+		var zero T
+		return zero, false
+	}
+	return pq.removeAt(item.index), true
+}
+
+// owns 检查句柄是否仍在当前队列中
+func (pq *PriorityQueue[T]) owns(item *PQItem[T]) bool {
+	return item != nil && item.index >= 0 && item.index < len(pq.items) && pq.items[item.index] == item
+}
+
+// removeAt 移除指定位置的元素：与最后一个元素交换后截断，再修复交换到该位置的元素
+func (pq *PriorityQueue[T]) removeAt(i int) T {
+	item := pq.items[i]
+	last := len(pq.items) - 1
+	if i != last {
+		pq.swap(i, last)
+	}
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	if i != last {
+		pq.fix(i)
+	}
+	item.index = -1
+	return item.value
+}
+
+// fix 在元素值变化后将其上浮或下沉到正确位置
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+// up 上浮
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.items[i].value, pq.items[parent].value) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down 下沉，返回元素是否发生了移动
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start := i
+	n := len(pq.items)
+	for {
+		smallest := 2*i + 1
+		if smallest >= n {
+			break
+		}
+		if right := smallest + 1; right < n && pq.less(pq.items[right].value, pq.items[smallest].value) {
+			smallest = right
+		}
+		if !pq.less(pq.items[smallest].value, pq.items[i].value) {
+			break
+		}
+		pq.swap(i, smallest)
+		i = smallest
+	}
+	return i > start
+}
+
+// swap 交换两个位置的元素并同步句柄中的索引
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+// Job 按截止时间调度的任务，实现了Comparable接口
+type Job struct {
+	Name     string
+	Deadline time.Time
+}
+
+// Compare 截止时间越早，优先级越高
+func (j Job) Compare(other Job) int {
+	return j.Deadline.Compare(other.Deadline)
+}
+
+// 16.1 优先队列演示
+func demonstratePriorityQueue() {
+	fmt.Println("\n=== 优先队列演示 ===")
+
+	now := time.Now()
+	jobs := NewPriorityQueue[Job](MinHeap)
+	jobs.Push(Job{Name: "生成报表", Deadline: now.Add(30 * time.Minute)})
+	jobs.Push(Job{Name: "发送邮件", Deadline: now.Add(5 * time.Minute)})
+	backup := jobs.Push(Job{Name: "备份数据库", Deadline: now.Add(2 * time.Hour)})
+	jobs.Push(Job{Name: "清理缓存", Deadline: now.Add(15 * time.Minute)})
+
+	// 备份任务的截止时间被提前，通过句柄更新它在堆中的位置
+	jobs.Update(backup, Job{Name: "备份数据库", Deadline: now.Add(1 * time.Minute)})
+
+	if next, ok := jobs.Peek(); ok {
+		fmt.Printf("下一个任务: %s\n", next.Name)
+	}
+	fmt.Println("按截止时间调度:")
+	for !jobs.IsEmpty() {
+		job, _ := jobs.Pop()
+		fmt.Printf("  %s (剩余 %v)\n", job.Name, job.Deadline.Sub(now).Round(time.Minute))
+	}
+
+	// 使用比较函数创建最大堆
+	scores := NewPriorityQueueFunc(func(a, b int) bool { return a < b }, MaxHeap)
+	for _, s := range []int{72, 95, 88, 60, 99} {
+		scores.Push(s)
+	}
+	fmt.Print("最大堆出队顺序: ")
+	for scores.Len() > 0 {
+		s, _ := scores.Pop()
+		fmt.Printf("%d ", s)
+	}
+	fmt.Println()
+}
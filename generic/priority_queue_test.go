@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueueModes(t *testing.T) {
+	values := []int{5, 1, 4, 1, 9, 2, 6}
+	tests := []struct {
+		mode HeapMode
+		want []int
+	}{
+		{MinHeap, []int{1, 1, 2, 4, 5, 6, 9}},
+		{MaxHeap, []int{9, 6, 5, 4, 2, 1, 1}},
+	}
+	for _, tt := range tests {
+		pq := NewPriorityQueueFunc(func(a, b int) bool { return a < b }, tt.mode)
+		for _, v := range values {
+			pq.Push(v)
+		}
+		var got []int
+		for !pq.IsEmpty() {
+			top, _ := pq.Peek()
+			v, _ := pq.Pop()
+			if top != v {
+				t.Fatalf("Peek() = %d, Pop() = %d", top, v)
+			}
+			got = append(got, v)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("mode %d popped %v, want %v", tt.mode, got, tt.want)
+		}
+		if _, ok := pq.Pop(); ok {
+			t.Errorf("Pop() on empty queue reported a value")
+		}
+	}
+}
+
+func TestPriorityQueueComparable(t *testing.T) {
+	now := time.Now()
+	pq := NewPriorityQueue[Job](MinHeap)
+	pq.Push(Job{"c", now.Add(3 * time.Hour)})
+	pq.Push(Job{"a", now.Add(time.Hour)})
+	pq.Push(Job{"b", now.Add(2 * time.Hour)})
+	for _, want := range []string{"a", "b", "c"} {
+		if j, _ := pq.Pop(); j.Name != want {
+			t.Fatalf("Pop() = %s, want %s", j.Name, want)
+		}
+	}
+}
+
+func TestPriorityQueueHandles(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pq := NewPriorityQueueFunc(func(a, b int) bool { return a < b }, MinHeap)
+	live := map[*PQItem[int]]bool{}
+	var dead []*PQItem[int]
+	for i := 0; i < 5000; i++ {
+		switch r.Intn(4) {
+		case 0, 1:
+			live[pq.Push(r.Intn(1000))] = true
+		case 2:
+			for item := range live {
+				v := r.Intn(1000)
+				if !pq.Update(item, v) || item.Value() != v {
+					t.Fatal("Update() on a live handle failed")
+				}
+				break
+			}
+		case 3:
+			for item := range live {
+				want := item.Value()
+				if v, ok := pq.Remove(item); !ok || v != want {
+					t.Fatalf("Remove() = %d, %v, want %d", v, ok, want)
+				}
+				delete(live, item)
+				dead = append(dead, item)
+				break
+			}
+		}
+		if pq.Len() != len(live) {
+			t.Fatalf("Len() = %d, want %d", pq.Len(), len(live))
+		}
+		if pq.Len() > 0 {
+			minimum := -1
+			for item := range live {
+				if minimum < 0 || item.Value() < minimum {
+					minimum = item.Value()
+				}
+			}
+			if top, _ := pq.Peek(); top != minimum {
+				t.Fatalf("Peek() = %d, want %d", top, minimum)
+			}
+		}
+	}
+	for _, item := range dead {
+		if pq.Update(item, 0) {
+			t.Fatal("Update() accepted a removed handle")
+		}
+		if _, ok := pq.Remove(item); ok {
+			t.Fatal("Remove() accepted a removed handle")
+		}
+	}
+	other := NewPriorityQueueFunc(func(a, b int) bool { return a < b }, MinHeap)
+	for item := range live {
+		if other.Update(item, 0) {
+			t.Fatal("Update() accepted a handle of another queue")
+		}
+		break
+	}
+}
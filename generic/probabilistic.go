@@ -0,0 +1,458 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"os"
+	"path/filepath"
+)
+
+// 28. 概率数据结构
+// BloomFilter 判断元素是否可能存在：不存在的判断一定准确，存在的判断有一定误判率
+// CountMinSketch 估计元素出现的次数：估计值不会偏小，偏大的程度由误差参数控制
+// 两者都只保存哈希后的位置，占用的内存与元素个数无关，只与期望容量和精度有关
+//
+// 默认使用 maphash.Comparable 计算哈希，种子在进程启动时随机生成，所以默认哈希写入文件后
+// 只能在同一进程内读回使用；需要跨进程持久化时，用Func版本的构造函数传入稳定的哈希函数，例如StringHash
+
+var (
+	// ErrIncompatibleSketch 两个结构的参数不同，无法合并
+	ErrIncompatibleSketch = errors.New("参数不一致，无法合并")
+	// ErrInvalidEncoding 编码数据格式错误
+	ErrInvalidEncoding = errors.New("编码数据格式错误")
+)
+
+// probSeed 默认哈希使用的进程级种子，所有结构共用，保证同一进程内创建的结构可以合并
+var probSeed = maphash.MakeSeed()
+
+// defaultHash 使用maphash计算任意可比较类型的哈希
+func defaultHash[T comparable](v T) uint64 {
+	return maphash.Comparable(probSeed, v)
+}
+
+// StringHash 稳定的字符串哈希（FNV-1a），不同进程中结果相同，适合需要持久化的场景
+func StringHash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// hashPair 由一个64位哈希派生出两个哈希，第i个位置为h1+i*h2（Kirsch-Mitzenmacher双重哈希）
+// h2取奇数，保证在2的幂长度下也能遍历到不同的位置
+func hashPair(h uint64) (uint64, uint64) {
+	h2 := bits.RotateLeft64(h, 32)*0x9E3779B97F4A7C15 | 1
+	return h, h2
+}
+
+// OptimalBloomSize 根据期望元素个数n和误判率p计算位数组长度m和哈希函数个数k
+// m = -n·ln(p)/ln²2，k = m/n·ln2
+func OptimalBloomSize(n int, p float64) (m uint64, k int) {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		panic("OptimalBloomSize: 误判率必须在(0, 1)之间")
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = max(1, int(math.Round(float64(m)/float64(n)*math.Ln2)))
+	return m, k
+}
+
+// BloomFilter 布隆过滤器
+type BloomFilter[T comparable] struct {
+	bits []uint64
+	m    uint64 // 位数
+	k    int    // 哈希函数个数
+	hash func(T) uint64
+}
+
+// NewBloomFilter 按期望元素个数和误判率创建布隆过滤器，使用默认哈希
+func NewBloomFilter[T comparable](expectedItems int, fpRate float64) *BloomFilter[T] {
+	return NewBloomFilterFunc(expectedItems, fpRate, defaultHash[T])
+}
+
+// NewBloomFilterFunc 使用指定哈希函数创建布隆过滤器
+func NewBloomFilterFunc[T comparable](expectedItems int, fpRate float64, hash func(T) uint64) *BloomFilter[T] {
+	m, k := OptimalBloomSize(expectedItems, fpRate)
+	return &BloomFilter[T]{bits: make([]uint64, (m+63)/64), m: m, k: k, hash: hash}
+}
+
+// Bits 返回位数组长度
+func (f *BloomFilter[T]) Bits() uint64 {
+	return f.m
+}
+
+// HashCount 返回哈希函数个数
+func (f *BloomFilter[T]) HashCount() int {
+	return f.k
+}
+
+// Add 添加元素
+func (f *BloomFilter[T]) Add(v T) {
+	h1, h2 := hashPair(f.hash(v))
+	for i := 0; i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		f.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// Contains 判断元素是否可能存在，返回false时一定不存在
+func (f *BloomFilter[T]) Contains(v T) bool {
+	h1, h2 := hashPair(f.hash(v))
+	for i := 0; i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// setBits 返回被置为1的位数
+func (f *BloomFilter[T]) setBits() uint64 {
+	n := 0
+	for _, w := range f.bits {
+		n += bits.OnesCount64(w)
+	}
+	return uint64(n)
+}
+
+// EstimatedCount 根据被置位的比例估计已添加的不同元素个数：n ≈ -m/k·ln(1-X/m)
+func (f *BloomFilter[T]) EstimatedCount() int {
+	x := float64(f.setBits())
+	if x >= float64(f.m) {
+		return math.MaxInt
+	}
+	return int(math.Round(-float64(f.m) / float64(f.k) * math.Log(1-x/float64(f.m))))
+}
+
+// FalsePositiveRate 根据当前被置位的比例估计误判率：(X/m)^k
+func (f *BloomFilter[T]) FalsePositiveRate() float64 {
+	return math.Pow(float64(f.setBits())/float64(f.m), float64(f.k))
+}
+
+// Merge 将other合并到f中，结果等价于两者所有元素的并集
+// 两个过滤器的位数和哈希函数个数必须相同，并且使用同一个哈希函数
+func (f *BloomFilter[T]) Merge(other *BloomFilter[T]) error {
+	if f.m != other.m || f.k != other.k {
+		return fmt.Errorf("布隆过滤器 m=%d,k=%d 与 m=%d,k=%d: %w", f.m, f.k, other.m, other.k, ErrIncompatibleSketch)
+	}
+	for i, w := range other.bits {
+		f.bits[i] |= w
+	}
+	return nil
+}
+
+// Clear 清空过滤器
+func (f *BloomFilter[T]) Clear() {
+	clear(f.bits)
+}
+
+// bloomMagic 布隆过滤器二进制格式的魔数
+const bloomMagic = "BLM1"
+
+// MarshalBinary 编码为二进制：魔数、位数、哈希函数个数、位数组，整数均为小端序
+// 哈希函数不会被编码，读回时使用接收者的哈希函数
+func (f BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(bloomMagic)+16+8*len(f.bits))
+	data = append(data, bloomMagic...)
+	data = binary.LittleEndian.AppendUint64(data, f.m)
+	data = binary.LittleEndian.AppendUint64(data, uint64(f.k))
+	for _, w := range f.bits {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary 从二进制数据恢复，接收者没有哈希函数时使用默认哈希
+// 零值过滤器的编码（位数和哈希函数个数都为0）恢复为零值
+func (f *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(bloomMagic)+16 || string(data[:len(bloomMagic)]) != bloomMagic {
+		return ErrInvalidEncoding
+	}
+	data = data[len(bloomMagic):]
+	m := binary.LittleEndian.Uint64(data)
+	k := binary.LittleEndian.Uint64(data[8:])
+	data = data[16:]
+	if m == 0 && k == 0 && len(data) == 0 {
+		*f = BloomFilter[T]{hash: f.hash}
+		return nil
+	}
+	words := uint64(len(data)) / 8
+	if len(data)%8 != 0 || m == 0 || m > 64*words || (m+63)/64 != words || k == 0 || k > math.MaxInt32 {
+		return ErrInvalidEncoding
+	}
+	f.bits = make([]uint64, words)
+	for i := range f.bits {
+		f.bits[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	f.m, f.k = m, int(k)
+	if f.hash == nil {
+		f.hash = defaultHash[T]
+	}
+	return nil
+}
+
+// WriteFile 将过滤器写入文件
+func (f *BloomFilter[T]) WriteFile(path string) error {
+	data, err := f.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadFile 从文件读回过滤器，覆盖当前内容
+func (f *BloomFilter[T]) ReadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := f.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("读取布隆过滤器 %s: %w", path, err)
+	}
+	return nil
+}
+
+// OptimalSketchSize 根据误差epsilon和置信度1-delta计算Count-Min Sketch的宽度和深度
+// 估计值以至少1-delta的概率不超过 真实值+epsilon·总数，width = e/epsilon，depth = ln(1/delta)
+func OptimalSketchSize(epsilon, delta float64) (width, depth int) {
+	if epsilon <= 0 || delta <= 0 || delta >= 1 {
+		panic("OptimalSketchSize: epsilon必须大于0，delta必须在(0, 1)之间")
+	}
+	width = int(math.Ceil(math.E / epsilon))
+	depth = max(1, int(math.Ceil(math.Log(1/delta))))
+	return width, depth
+}
+
+// CountMinSketch 频率估计草图，每行一个哈希函数，估计值取各行计数的最小值
+type CountMinSketch[T comparable] struct {
+	counts []uint64 // depth行width列
+	width  int
+	depth  int
+	total  uint64
+	hash   func(T) uint64
+}
+
+// NewCountMinSketch 按误差和置信度创建Count-Min Sketch，使用默认哈希
+func NewCountMinSketch[T comparable](epsilon, delta float64) *CountMinSketch[T] {
+	return NewCountMinSketchFunc(epsilon, delta, defaultHash[T])
+}
+
+// NewCountMinSketchFunc 使用指定哈希函数创建Count-Min Sketch
+func NewCountMinSketchFunc[T comparable](epsilon, delta float64, hash func(T) uint64) *CountMinSketch[T] {
+	width, depth := OptimalSketchSize(epsilon, delta)
+	return &CountMinSketch[T]{counts: make([]uint64, width*depth), width: width, depth: depth, hash: hash}
+}
+
+// Width 返回每行的计数器个数
+func (s *CountMinSketch[T]) Width() int {
+	return s.width
+}
+
+// Depth 返回行数
+func (s *CountMinSketch[T]) Depth() int {
+	return s.depth
+}
+
+// Total 返回所有元素的计数之和
+func (s *CountMinSketch[T]) Total() uint64 {
+	return s.total
+}
+
+// Add 将元素的计数增加n
+func (s *CountMinSketch[T]) Add(v T, n uint64) {
+	h1, h2 := hashPair(s.hash(v))
+	for row := 0; row < s.depth; row++ {
+		col := (h1 + uint64(row)*h2) % uint64(s.width)
+		s.counts[row*s.width+int(col)] += n
+	}
+	s.total += n
+}
+
+// Count 估计元素的出现次数，结果不小于真实值
+func (s *CountMinSketch[T]) Count(v T) uint64 {
+	h1, h2 := hashPair(s.hash(v))
+	estimate := uint64(math.MaxUint64)
+	for row := 0; row < s.depth; row++ {
+		col := (h1 + uint64(row)*h2) % uint64(s.width)
+		estimate = min(estimate, s.counts[row*s.width+int(col)])
+	}
+	return estimate
+}
+
+// Merge 将other的计数累加到s中，两者的宽度和深度必须相同，并且使用同一个哈希函数
+func (s *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return fmt.Errorf("Count-Min Sketch %dx%d 与 %dx%d: %w", s.depth, s.width, other.depth, other.width, ErrIncompatibleSketch)
+	}
+	for i, c := range other.counts {
+		s.counts[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Clear 清空所有计数
+func (s *CountMinSketch[T]) Clear() {
+	clear(s.counts)
+	s.total = 0
+}
+
+// sketchMagic Count-Min Sketch二进制格式的魔数
+const sketchMagic = "CMS1"
+
+// MarshalBinary 编码为二进制：魔数、宽度、深度、总数、计数器，整数均为小端序
+func (s CountMinSketch[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(sketchMagic)+24+8*len(s.counts))
+	data = append(data, sketchMagic...)
+	data = binary.LittleEndian.AppendUint64(data, uint64(s.width))
+	data = binary.LittleEndian.AppendUint64(data, uint64(s.depth))
+	data = binary.LittleEndian.AppendUint64(data, s.total)
+	for _, c := range s.counts {
+		data = binary.LittleEndian.AppendUint64(data, c)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary 从二进制数据恢复，接收者没有哈希函数时使用默认哈希
+// 零值草图的编码（宽度和深度都为0）恢复为零值
+func (s *CountMinSketch[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(sketchMagic)+24 || string(data[:len(sketchMagic)]) != sketchMagic {
+		return ErrInvalidEncoding
+	}
+	data = data[len(sketchMagic):]
+	width := binary.LittleEndian.Uint64(data)
+	depth := binary.LittleEndian.Uint64(data[8:])
+	total := binary.LittleEndian.Uint64(data[16:])
+	data = data[24:]
+	if width == 0 && depth == 0 && total == 0 && len(data) == 0 {
+		*s = CountMinSketch[T]{hash: s.hash}
+		return nil
+	}
+	n := uint64(len(data)) / 8
+	if len(data)%8 != 0 || width == 0 || depth == 0 || n%width != 0 || n/width != depth {
+		return ErrInvalidEncoding
+	}
+	s.counts = make([]uint64, n)
+	for i := range s.counts {
+		s.counts[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	s.width, s.depth, s.total = int(width), int(depth), total
+	if s.hash == nil {
+		s.hash = defaultHash[T]
+	}
+	return nil
+}
+
+// WriteFile 将草图写入文件
+func (s *CountMinSketch[T]) WriteFile(path string) error {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadFile 从文件读回草图，覆盖当前内容
+func (s *CountMinSketch[T]) ReadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("读取Count-Min Sketch %s: %w", path, err)
+	}
+	return nil
+}
+
+// 28.1 概率数据结构演示
+func demonstrateProbabilistic() {
+	fmt.Println("\n=== 概率数据结构演示 ===")
+
+	m, k := OptimalBloomSize(1_000_000, 0.01)
+	fmt.Printf("100万个元素、误判率1%%: 需要%d位(%.2fMB), %d个哈希函数\n", m, float64(m)/8/1024/1024, k)
+
+	// 误判率实测
+	seen := NewBloomFilter[int](10_000, 0.01)
+	for i := 0; i < 10_000; i++ {
+		seen.Add(i)
+	}
+	falsePositives := 0
+	for i := 10_000; i < 110_000; i++ {
+		if seen.Contains(i) {
+			falsePositives++
+		}
+	}
+	fmt.Printf("插入1万个整数: 估计元素数=%d, 估计误判率=%.4f, 实测误判率=%.4f\n",
+		seen.EstimatedCount(), seen.FalsePositiveRate(), float64(falsePositives)/100_000)
+
+	// 重复文件检测：用内容摘要作为键，过滤器挡在精确比较之前
+	files := []Pair[string, string]{
+		{"a.txt", "hello"},
+		{"b.txt", "world"},
+		{"a_copy.txt", "hello"},
+		{"c.txt", "golang"},
+	}
+	digests := NewBloomFilterFunc(1000, 0.001, StringHash)
+	exact := NewSet[string]()
+	for _, file := range files {
+		sum := sha256.Sum256([]byte(file.Value))
+		digest := hex.EncodeToString(sum[:])
+		if digests.Contains(digest) && exact.Contains(digest) {
+			fmt.Printf("%s 是重复文件\n", file.Key)
+		}
+		digests.Add(digest)
+		exact.Add(digest)
+	}
+
+	// 稳定哈希的过滤器写入磁盘后可以在下次运行时读回
+	path := filepath.Join(os.TempDir(), "digests.bloom")
+	if err := digests.WriteFile(path); err != nil {
+		fmt.Println("写入失败:", err)
+		return
+	}
+	defer os.Remove(path)
+	loaded := NewBloomFilterFunc(1, 0.5, StringHash)
+	if err := loaded.ReadFile(path); err != nil {
+		fmt.Println("读取失败:", err)
+		return
+	}
+	sum := sha256.Sum256([]byte("golang"))
+	fmt.Printf("从文件读回: %d位, %d个哈希函数, 包含golang的摘要=%v\n",
+		loaded.Bits(), loaded.HashCount(), loaded.Contains(hex.EncodeToString(sum[:])))
+
+	// 合并两台机器上的过滤器
+	left, right := NewBloomFilter[string](100, 0.01), NewBloomFilter[string](100, 0.01)
+	left.Add("server-1")
+	right.Add("server-2")
+	if err := left.Merge(right); err == nil {
+		fmt.Printf("合并后 server-1=%v server-2=%v server-3=%v\n",
+			left.Contains("server-1"), left.Contains("server-2"), left.Contains("server-3"))
+	}
+	if err := left.Merge(NewBloomFilter[string](5000, 0.01)); err != nil {
+		fmt.Println("合并失败:", err)
+	}
+
+	// Count-Min Sketch：统计高频访问路径
+	sketch := NewCountMinSketch[string](0.001, 0.01)
+	fmt.Printf("Count-Min Sketch 误差0.1%%、置信度99%%: %d行x%d列\n", sketch.Depth(), sketch.Width())
+	hits := map[string]uint64{"/": 5000, "/login": 1200, "/api/users": 800, "/about": 3}
+	for path, n := range hits {
+		sketch.Add(path, n)
+	}
+	for i := 0; i < 2000; i++ {
+		sketch.Add(fmt.Sprintf("/item/%d", i), 1)
+	}
+	for _, path := range []string{"/", "/login", "/api/users", "/about", "/missing"} {
+		fmt.Printf("  %-11s 真实=%-5d 估计=%d\n", path, hits[path], sketch.Count(path))
+	}
+	fmt.Printf("  总访问次数: %d\n", sketch.Total())
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestOptimalSizes(t *testing.T) {
+	// 1000个元素、1%误判率：m = ⌈9585.06⌉，k = round(6.64)
+	if m, k := OptimalBloomSize(1000, 0.01); m != 9586 || k != 7 {
+		t.Errorf("OptimalBloomSize(1000, 0.01) = %d, %d", m, k)
+	}
+	if w, d := OptimalSketchSize(0.001, 0.01); w != 2719 || d != 5 {
+		t.Errorf("OptimalSketchSize(0.001, 0.01) = %d, %d", w, d)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("OptimalBloomSize(10, 1) did not panic")
+		}
+	}()
+	OptimalBloomSize(10, 1)
+}
+
+func TestBloomFilter(t *testing.T) {
+	const n, p = 10000, 0.01
+	f := NewBloomFilter[int](n, p)
+	for i := 0; i < n; i++ {
+		f.Add(i)
+	}
+	for i := 0; i < n; i++ {
+		if !f.Contains(i) {
+			t.Fatalf("Contains(%d) = false for an added element", i)
+		}
+	}
+	falsePositives := 0
+	for i := n; i < 11*n; i++ {
+		if f.Contains(i) {
+			falsePositives++
+		}
+	}
+	// 实测误判率应当接近设定值
+	if rate := float64(falsePositives) / (10 * n); rate > 2*p {
+		t.Errorf("false positive rate %.4f, want about %.2f", rate, p)
+	}
+	if est := f.EstimatedCount(); math.Abs(float64(est-n)) > 0.05*n {
+		t.Errorf("EstimatedCount() = %d, want about %d", est, n)
+	}
+	if r := f.FalsePositiveRate(); r > 2*p {
+		t.Errorf("FalsePositiveRate() = %.4f", r)
+	}
+
+	f.Clear()
+	if f.Contains(0) || f.EstimatedCount() != 0 {
+		t.Error("Clear() left bits set")
+	}
+}
+
+func TestBloomFilterMerge(t *testing.T) {
+	a := NewBloomFilterFunc(1000, 0.01, StringHash)
+	b := NewBloomFilterFunc(1000, 0.01, StringHash)
+	a.Add("apple")
+	b.Add("banana")
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Contains("apple") || !a.Contains("banana") {
+		t.Error("merged filter lost an element")
+	}
+	if err := a.Merge(NewBloomFilterFunc(10, 0.01, StringHash)); !errors.Is(err, ErrIncompatibleSketch) {
+		t.Errorf("Merge with different size = %v", err)
+	}
+}
+
+func TestBloomFilterEncoding(t *testing.T) {
+	f := NewBloomFilterFunc(500, 0.01, StringHash)
+	for i := 0; i < 500; i++ {
+		f.Add(strconv.Itoa(i))
+	}
+	path := filepath.Join(t.TempDir(), "bloom.bin")
+	if err := f.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	// 零值接收者使用默认哈希，这里显式指定与写入时相同的稳定哈希
+	g := &BloomFilter[string]{hash: StringHash}
+	if err := g.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if g.Bits() != f.Bits() || g.HashCount() != f.HashCount() {
+		t.Fatalf("parameters m=%d,k=%d, want m=%d,k=%d", g.Bits(), g.HashCount(), f.Bits(), f.HashCount())
+	}
+	for i := 0; i < 500; i++ {
+		if !g.Contains(strconv.Itoa(i)) {
+			t.Fatalf("decoded filter lost %d", i)
+		}
+	}
+
+	// 截断或篡改的数据都应被拒绝
+	data, _ := f.MarshalBinary()
+	for i := 0; i < len(data); i++ {
+		if err := new(BloomFilter[string]).UnmarshalBinary(data[:i]); !errors.Is(err, ErrInvalidEncoding) {
+			t.Fatalf("UnmarshalBinary of %d/%d bytes = %v", i, len(data), err)
+		}
+	}
+	bad := append([]byte(nil), data...)
+	bad[0] = 'X'
+	if err := new(BloomFilter[string]).UnmarshalBinary(bad); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("UnmarshalBinary with bad magic = %v", err)
+	}
+	if err := g.ReadFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ReadFile of a missing file succeeded")
+	}
+}
+
+func TestCountMinSketch(t *testing.T) {
+	const epsilon, delta = 0.001, 0.01
+	s := NewCountMinSketch[int](epsilon, delta)
+	r := rand.New(rand.NewSource(1))
+	ref := map[int]uint64{}
+	for i := 0; i < 100000; i++ {
+		// 幂律分布，少数元素出现次数很多
+		k := int(math.Pow(r.Float64(), 3) * 5000)
+		n := uint64(1 + r.Intn(3))
+		s.Add(k, n)
+		ref[k] += n
+	}
+	var total uint64
+	exceeded := 0
+	for k, want := range ref {
+		total += want
+		got := s.Count(k)
+		if got < want {
+			t.Fatalf("Count(%d) = %d, less than the true count %d", k, got, want)
+		}
+		if float64(got-want) > epsilon*float64(s.Total()) {
+			exceeded++
+		}
+	}
+	if s.Total() != total {
+		t.Errorf("Total() = %d, want %d", s.Total(), total)
+	}
+	// 超出误差上限的比例不应明显高于delta
+	if rate := float64(exceeded) / float64(len(ref)); rate > 2*delta {
+		t.Errorf("%.3f of estimates exceed the error bound, want at most %.2f", rate, delta)
+	}
+	// 单个元素超过10倍误差上限的概率可以忽略
+	if s.Count(-1) > uint64(10*epsilon*float64(s.Total())) {
+		t.Errorf("Count of an unseen element = %d", s.Count(-1))
+	}
+}
+
+func TestCountMinSketchMergeAndEncoding(t *testing.T) {
+	a := NewCountMinSketchFunc(0.01, 0.01, StringHash)
+	b := NewCountMinSketchFunc(0.01, 0.01, StringHash)
+	a.Add("x", 3)
+	b.Add("x", 4)
+	b.Add("y", 1)
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Count("x") < 7 || a.Total() != 8 {
+		t.Errorf("after Merge: Count(x) = %d, Total() = %d", a.Count("x"), a.Total())
+	}
+	if err := a.Merge(NewCountMinSketchFunc(0.1, 0.01, StringHash)); !errors.Is(err, ErrIncompatibleSketch) {
+		t.Errorf("Merge with different size = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "sketch.bin")
+	if err := a.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	c := &CountMinSketch[string]{hash: StringHash}
+	if err := c.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if c.Width() != a.Width() || c.Depth() != a.Depth() || c.Total() != a.Total() || c.Count("x") != a.Count("x") {
+		t.Errorf("decoded sketch differs: %dx%d total %d", c.Depth(), c.Width(), c.Total())
+	}
+
+	data, _ := a.MarshalBinary()
+	for i := 0; i < len(data); i += 7 {
+		if err := new(CountMinSketch[string]).UnmarshalBinary(data[:i]); !errors.Is(err, ErrInvalidEncoding) {
+			t.Fatalf("UnmarshalBinary of %d/%d bytes = %v", i, len(data), err)
+		}
+	}
+
+	a.Clear()
+	if a.Total() != 0 || a.Count("x") != 0 {
+		t.Error("Clear() left counts")
+	}
+}
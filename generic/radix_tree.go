@@ -0,0 +1,391 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 25. 基数树（压缩前缀树）
+// 只有一个子节点的路径会被压缩成一条边，节点数与键的数量成正比
+// RadixTree 按字符（rune）边界拆分边，中文等多字节UTF-8键不会被拆成半个字符
+// ByteRadixTree 按字节拆分，适合任意二进制键
+
+// radixNode 基数树节点
+type radixNode[V any] struct {
+	label    string          // 从父节点到该节点的边
+	children []*radixNode[V] // 按首个拆分单位排序，任意两个子节点的首个单位都不同
+	leaf     bool            // 是否存储了值
+	value    V
+}
+
+// radixTree 基数树的公共实现，aligned为true时按rune边界拆分
+type radixTree[V any] struct {
+	root    radixNode[V]
+	size    int
+	aligned bool
+}
+
+// unitLen 返回s第一个拆分单位的长度：字节或完整的rune
+func (t *radixTree[V]) unitLen(s string) int {
+	if !t.aligned {
+		return 1
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
+
+// commonPrefixLen 返回a和b公共前缀的长度，按拆分单位逐个比较
+func (t *radixTree[V]) commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) {
+		ua, ub := t.unitLen(a[i:]), t.unitLen(b[i:])
+		if ua != ub || a[i:i+ua] != b[i:i+ub] {
+			break
+		}
+		i += ua
+	}
+	return i
+}
+
+// firstUnit 返回s的第一个拆分单位
+func (t *radixTree[V]) firstUnit(s string) string {
+	return s[:t.unitLen(s)]
+}
+
+// findChild 二分查找首个拆分单位与key相同的子节点，找不到时返回key应插入的位置
+// 子节点按首个单位而不是整个label排序：拆分边时首个单位不变，已有的顺序不会被破坏
+func (t *radixTree[V]) findChild(n *radixNode[V], key string) (int, bool) {
+	unit := t.firstUnit(key)
+	i := sort.Search(len(n.children), func(i int) bool {
+		return t.firstUnit(n.children[i].label) >= unit
+	})
+	return i, i < len(n.children) && t.firstUnit(n.children[i].label) == unit
+}
+
+// insert 插入或更新键值，返回是否为新键
+func (t *radixTree[V]) insert(key string, value V) bool {
+	n := &t.root
+	for key != "" {
+		i, ok := t.findChild(n, key)
+		if !ok {
+			leaf := &radixNode[V]{label: key, leaf: true, value: value}
+			n.children = slices.Insert(n.children, i, leaf)
+			t.size++
+			return true
+		}
+
+		child := n.children[i]
+		l := t.commonPrefixLen(child.label, key)
+		if l < len(child.label) {
+			// key与边只有部分相同，在公共前缀处拆分出中间节点
+			mid := &radixNode[V]{label: child.label[:l], children: []*radixNode[V]{child}}
+			child.label = child.label[l:]
+			n.children[i] = mid
+			child = mid
+		}
+		n = child
+		key = key[l:]
+	}
+
+	isNew := !n.leaf
+	n.leaf = true
+	n.value = value
+	if isNew {
+		t.size++
+	}
+	return isNew
+}
+
+// get 查找键
+func (t *radixTree[V]) get(key string) (V, bool) {
+	n := &t.root
+	for key != "" {
+		i, ok := t.findChild(n, key)
+		if !ok || !strings.HasPrefix(key, n.children[i].label) {
+			var zero V
+			return zero, false
+		}
+		n = n.children[i]
+		key = key[len(n.label):]
+	}
+	return n.value, n.leaf
+}
+
+// delete 删除键，并合并删除后只剩一个子节点的节点
+func (t *radixTree[V]) delete(key string) bool {
+	n := &t.root
+	var parent *radixNode[V]
+	var index int // n在parent.children中的位置
+	for key != "" {
+		i, ok := t.findChild(n, key)
+		if !ok || !strings.HasPrefix(key, n.children[i].label) {
+			return false
+		}
+		parent, index = n, i
+		n = n.children[i]
+		key = key[len(n.label):]
+	}
+	if !n.leaf {
+		return false
+	}
+
+	var zero V
+	n.leaf = false
+	n.value = zero
+	t.size--
+
+	if parent == nil {
+		return true
+	}
+	switch len(n.children) {
+	case 0:
+		parent.children = slices.Delete(parent.children, index, index+1)
+		// 父节点不是根、没有值且只剩一个子节点时与子节点合并
+		if parent != &t.root && !parent.leaf && len(parent.children) == 1 {
+			parent.merge()
+		}
+	case 1:
+		n.merge()
+	}
+	return true
+}
+
+// merge 将唯一的子节点合并到当前节点
+func (n *radixNode[V]) merge() {
+	child := n.children[0]
+	n.label += child.label
+	n.children = child.children
+	n.leaf = child.leaf
+	n.value = child.value
+}
+
+// longestPrefix 查找是s前缀的最长键
+func (t *radixTree[V]) longestPrefix(s string) (string, V, bool) {
+	n := &t.root
+	var (
+		matched  int
+		value    V
+		found    = n.leaf
+		consumed int
+	)
+	if found {
+		value = n.value
+	}
+	rest := s
+	for rest != "" {
+		i, ok := t.findChild(n, rest)
+		if !ok || !strings.HasPrefix(rest, n.children[i].label) {
+			break
+		}
+		n = n.children[i]
+		consumed += len(n.label)
+		rest = rest[len(n.label):]
+		if n.leaf {
+			matched, value, found = consumed, n.value, true
+		}
+	}
+	return s[:matched], value, found
+}
+
+// walkPrefix 遍历所有以prefix开头的键，键为有效UTF-8时按字典序产生
+// prefix按字节匹配，即使它以不完整的UTF-8字符结尾也能得到正确结果
+func (t *radixTree[V]) walkPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.walkMatch(&t.root, "", prefix, yield)
+	}
+}
+
+// walkMatch 在n的子树中遍历以rest开头的键，key为到达n的完整键
+func (t *radixTree[V]) walkMatch(n *radixNode[V], key, rest string, yield func(string, V) bool) bool {
+	for rest != "" {
+		if t.aligned && !utf8.FullRuneInString(rest) {
+			// rest以不完整的字符结尾，首字节相同的多个子节点都可能匹配，逐个检查
+			for _, child := range n.children {
+				switch {
+				case strings.HasPrefix(child.label, rest):
+					if !child.walk(key+child.label, yield) {
+						return false
+					}
+				case strings.HasPrefix(rest, child.label):
+					if !t.walkMatch(child, key+child.label, rest[len(child.label):], yield) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+
+		i, ok := t.findChild(n, rest)
+		if !ok {
+			return true
+		}
+		child := n.children[i]
+		if strings.HasPrefix(child.label, rest) {
+			// 前缀在边的中间或末尾结束，整棵子树都匹配
+			return child.walk(key+child.label, yield)
+		}
+		if !strings.HasPrefix(rest, child.label) {
+			return true
+		}
+		n, key, rest = child, key+child.label, rest[len(child.label):]
+	}
+	return n.walk(key, yield)
+}
+
+// walk 先序遍历子树，key为到达当前节点的完整键
+func (n *radixNode[V]) walk(key string, yield func(string, V) bool) bool {
+	if n.leaf && !yield(key, n.value) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.walk(key+child.label, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// RadixTree 以字符串为键的基数树
+type RadixTree[V any] struct {
+	t radixTree[V]
+}
+
+// NewRadixTree 创建基数树
+func NewRadixTree[V any]() *RadixTree[V] {
+	return &RadixTree[V]{t: radixTree[V]{aligned: true}}
+}
+
+// Len 返回键的数量
+func (r *RadixTree[V]) Len() int {
+	return r.t.size
+}
+
+// Insert 插入或更新键值，返回是否为新键
+func (r *RadixTree[V]) Insert(key string, value V) bool {
+	return r.t.insert(key, value)
+}
+
+// Get 查找键
+func (r *RadixTree[V]) Get(key string) (V, bool) {
+	return r.t.get(key)
+}
+
+// Delete 删除键，返回键是否存在
+func (r *RadixTree[V]) Delete(key string) bool {
+	return r.t.delete(key)
+}
+
+// LongestPrefix 查找是s前缀的最长键，常用于路由表匹配
+func (r *RadixTree[V]) LongestPrefix(s string) (string, V, bool) {
+	return r.t.longestPrefix(s)
+}
+
+// WalkPrefix 按字典序遍历所有以prefix开头的键，常用于自动补全
+func (r *RadixTree[V]) WalkPrefix(prefix string) iter.Seq2[string, V] {
+	return r.t.walkPrefix(prefix)
+}
+
+// All 按字典序遍历所有键值
+func (r *RadixTree[V]) All() iter.Seq2[string, V] {
+	return r.t.walkPrefix("")
+}
+
+// ByteRadixTree 以字节切片为键的基数树
+type ByteRadixTree[V any] struct {
+	t radixTree[V]
+}
+
+// NewByteRadixTree 创建字节键基数树
+func NewByteRadixTree[V any]() *ByteRadixTree[V] {
+	return &ByteRadixTree[V]{}
+}
+
+// Len 返回键的数量
+func (r *ByteRadixTree[V]) Len() int {
+	return r.t.size
+}
+
+// Insert 插入或更新键值，返回是否为新键
+func (r *ByteRadixTree[V]) Insert(key []byte, value V) bool {
+	return r.t.insert(string(key), value)
+}
+
+// Get 查找键
+func (r *ByteRadixTree[V]) Get(key []byte) (V, bool) {
+	return r.t.get(string(key))
+}
+
+// Delete 删除键，返回键是否存在
+func (r *ByteRadixTree[V]) Delete(key []byte) bool {
+	return r.t.delete(string(key))
+}
+
+// LongestPrefix 查找是s前缀的最长键
+func (r *ByteRadixTree[V]) LongestPrefix(s []byte) ([]byte, V, bool) {
+	key, value, ok := r.t.longestPrefix(string(s))
+	return s[:len(key)], value, ok
+}
+
+// WalkPrefix 按字节序遍历所有以prefix开头的键，每次产生的键都是新分配的切片
+func (r *ByteRadixTree[V]) WalkPrefix(prefix []byte) iter.Seq2[[]byte, V] {
+	return func(yield func([]byte, V) bool) {
+		for key, value := range r.t.walkPrefix(string(prefix)) {
+			if !yield([]byte(key), value) {
+				return
+			}
+		}
+	}
+}
+
+// 25.1 基数树演示
+func demonstrateRadixTree() {
+	fmt.Println("\n=== 基数树演示 ===")
+
+	// 路由表：最长前缀匹配
+	routes := NewRadixTree[string]()
+	routes.Insert("/", "首页")
+	routes.Insert("/api", "API入口")
+	routes.Insert("/api/users", "用户列表")
+	routes.Insert("/api/users/profile", "用户资料")
+	routes.Insert("/static", "静态文件")
+
+	for _, path := range []string{"/api/users/42", "/api/orders", "/static/logo.png", "/about"} {
+		if route, handler, ok := routes.LongestPrefix(path); ok {
+			fmt.Printf("路由 %-20s -> %-12s (%s)\n", path, route, handler)
+		}
+	}
+
+	// 自动补全：中文多字节键
+	cities := NewRadixTree[int]()
+	for i, city := range []string{"北京", "北京市", "北海", "南京", "南宁", "南昌", "上海"} {
+		cities.Insert(city, i)
+	}
+	fmt.Print("以\"北\"开头: ")
+	for key := range cities.WalkPrefix("北") {
+		fmt.Printf("%s ", key)
+	}
+	fmt.Print("\n以\"南\"开头: ")
+	for key := range cities.WalkPrefix("南") {
+		fmt.Printf("%s ", key)
+	}
+	fmt.Println()
+
+	cities.Delete("北京")
+	_, ok := cities.Get("北京")
+	v, ok2 := cities.Get("北京市")
+	fmt.Printf("删除\"北京\"后: 北京存在=%v, 北京市=%d(%v), 共%d个键\n", ok, v, ok2, cities.Len())
+
+	// 字节键
+	prefixes := NewByteRadixTree[string]()
+	prefixes.Insert([]byte{0x89, 'P', 'N', 'G'}, "PNG")
+	prefixes.Insert([]byte{0xFF, 0xD8, 0xFF}, "JPEG")
+	prefixes.Insert([]byte("GIF8"), "GIF")
+	header := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10}
+	if magic, kind, ok := prefixes.LongestPrefix(header); ok {
+		fmt.Printf("文件头 % X 识别为 %s (魔数 % X)\n", header, kind, magic)
+	}
+}
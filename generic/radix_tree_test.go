@@ -0,0 +1,221 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// randomKey 从units中随机拼接出最多4个单位的键，单位较少时能产生大量公共前缀
+func randomKey(r *rand.Rand, units []string) string {
+	var sb strings.Builder
+	for range r.Intn(5) {
+		sb.WriteString(units[r.Intn(len(units))])
+	}
+	return sb.String()
+}
+
+// checkRadixNode 检查基数树的结构：非根节点的边非空，没有值的节点至少有两个子节点，子节点有序
+func checkRadixNode[V any](t *testing.T, n *radixNode[V], root bool) {
+	t.Helper()
+	if !root {
+		if n.label == "" {
+			t.Fatal("non-root node with empty label")
+		}
+		if !n.leaf && len(n.children) < 2 {
+			t.Fatalf("node %q has %d children and no value, should have been merged", n.label, len(n.children))
+		}
+	}
+	for i := 1; i < len(n.children); i++ {
+		if n.children[i-1].label >= n.children[i].label {
+			t.Fatalf("children %q and %q out of order", n.children[i-1].label, n.children[i].label)
+		}
+	}
+	for _, c := range n.children {
+		checkRadixNode(t, c, false)
+	}
+}
+
+// prefixedKeys 返回ref中以prefix开头的键，按字典序排列
+func prefixedKeys(ref map[string]int, prefix string) []string {
+	var keys []string
+	for k := range ref {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestRadixTreeRandomOps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	units := []string{"a", "b", "中", "丁", "北", "京", "é"}
+	tree := NewRadixTree[int]()
+	ref := map[string]int{}
+
+	for i := 0; i < 20000; i++ {
+		k := randomKey(r, units)
+		_, exists := ref[k]
+		if r.Intn(3) < 2 {
+			if tree.Insert(k, i) == exists {
+				t.Fatalf("Insert(%q) reported new = %v", k, !exists)
+			}
+			ref[k] = i
+		} else {
+			if tree.Delete(k) != exists {
+				t.Fatalf("Delete(%q) = %v, want %v", k, !exists, exists)
+			}
+			delete(ref, k)
+		}
+		if tree.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", tree.Len(), len(ref))
+		}
+	}
+	checkRadixNode(t, &tree.t.root, true)
+
+	for k, v := range ref {
+		if got, ok := tree.Get(k); !ok || got != v {
+			t.Fatalf("Get(%q) = %d, %v, want %d", k, got, ok, v)
+		}
+	}
+	for i := 0; i < 2000; i++ {
+		q := randomKey(r, units) + randomKey(r, units)
+		best := -1
+		for k := range ref {
+			if strings.HasPrefix(q, k) && len(k) > best {
+				best = len(k)
+			}
+		}
+		lp, v, ok := tree.LongestPrefix(q)
+		if ok != (best >= 0) || ok && (len(lp) != best || ref[lp] != v) {
+			t.Fatalf("LongestPrefix(%q) = %q, %v, want length %d", q, lp, ok, best)
+		}
+
+		// 以不完整的UTF-8字符结尾的前缀也要正确匹配
+		p := randomKey(r, units)
+		if r.Intn(3) == 0 && p != "" {
+			p = p[:len(p)-1]
+		}
+		var got []string
+		for k, v := range tree.WalkPrefix(p) {
+			if ref[k] != v {
+				t.Fatalf("WalkPrefix yielded %q=%d, want %d", k, v, ref[k])
+			}
+			got = append(got, k)
+		}
+		if want := prefixedKeys(ref, p); !slices.Equal(got, want) {
+			t.Fatalf("WalkPrefix(%q) = %q, want %q", p, got, want)
+		}
+	}
+}
+
+func TestRadixTreeRuneBoundaries(t *testing.T) {
+	tree := NewRadixTree[int]()
+	// 丁(e4 b8 81)和中(e4 b8 ad)的前两个字节相同，边不能在字符中间拆开
+	tree.Insert("丁一", 1)
+	tree.Insert("中国", 2)
+	tree.Insert("中", 3)
+	checkRadixNode(t, &tree.t.root, true)
+	for _, c := range tree.t.root.children {
+		if !strings.HasPrefix(c.label, "丁") && !strings.HasPrefix(c.label, "中") {
+			t.Errorf("edge %q splits a rune", c.label)
+		}
+	}
+	var keys []string
+	for k := range tree.WalkPrefix("\xe4\xb8") {
+		keys = append(keys, k)
+	}
+	if !slices.Equal(keys, []string{"丁一", "中", "中国"}) {
+		t.Errorf("WalkPrefix of a partial rune = %q", keys)
+	}
+	if k, v, ok := tree.LongestPrefix("中国人"); k != "中国" || v != 2 || !ok {
+		t.Errorf("LongestPrefix = %q, %d, %v", k, v, ok)
+	}
+	if _, _, ok := tree.LongestPrefix("北京"); ok {
+		t.Error("LongestPrefix matched without a prefix key")
+	}
+
+	tree.Insert("", 0)
+	if k, v, ok := tree.LongestPrefix("北京"); k != "" || v != 0 || !ok {
+		t.Errorf("LongestPrefix with empty key = %q, %d, %v", k, v, ok)
+	}
+}
+
+func TestByteRadixTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	// 按字节拆分时任意字节序列都是合法的键
+	units := []string{"\xe4", "\xb8", "\x81", "a", "丁", "\xe4\xb8"}
+	tree := NewByteRadixTree[int]()
+	ref := map[string]int{}
+	for i := 0; i < 20000; i++ {
+		k := randomKey(r, units)
+		_, exists := ref[k]
+		if r.Intn(3) < 2 {
+			if tree.Insert([]byte(k), i) == exists {
+				t.Fatalf("Insert(%q) reported new = %v", k, !exists)
+			}
+			ref[k] = i
+		} else {
+			if tree.Delete([]byte(k)) != exists {
+				t.Fatalf("Delete(%q) = %v, want %v", k, !exists, exists)
+			}
+			delete(ref, k)
+		}
+	}
+	checkRadixNode(t, &tree.t.root, true)
+	if tree.Len() != len(ref) {
+		t.Fatalf("Len() = %d, want %d", tree.Len(), len(ref))
+	}
+	for k, v := range ref {
+		if got, ok := tree.Get([]byte(k)); !ok || got != v {
+			t.Fatalf("Get(%q) = %d, %v, want %d", k, got, ok, v)
+		}
+	}
+	for range 500 {
+		p := randomKey(r, units)
+		var got []string
+		for k := range tree.WalkPrefix([]byte(p)) {
+			got = append(got, string(k))
+		}
+		if want := prefixedKeys(ref, p); !slices.Equal(got, want) {
+			t.Fatalf("WalkPrefix(%q) = %q, want %q", p, got, want)
+		}
+	}
+
+	// 返回的前缀引用输入切片，WalkPrefix产生的键是独立的副本
+	input := []byte("\xe4\xb8\x81abc")
+	if k, _, ok := tree.LongestPrefix(input); ok && len(k) > 0 && &k[0] != &input[0] {
+		t.Error("LongestPrefix did not return a subslice of its input")
+	}
+	for k := range tree.WalkPrefix(nil) {
+		if len(k) > 0 {
+			k[0] ^= 0xff
+			break
+		}
+	}
+	for k, v := range ref {
+		if got, ok := tree.Get([]byte(k)); !ok || got != v {
+			t.Fatal("modifying a key from WalkPrefix changed the tree")
+		}
+	}
+}
+
+func TestRadixTreeEarlyExit(t *testing.T) {
+	tree := NewRadixTree[int]()
+	for i, k := range []string{"a", "ab", "abc", "b", "ba"} {
+		tree.Insert(k, i)
+	}
+	var keys []string
+	for k := range tree.All() {
+		keys = append(keys, k)
+		if len(keys) == 3 {
+			break
+		}
+	}
+	if !slices.Equal(keys, []string{"a", "ab", "abc"}) {
+		t.Errorf("All() with break = %v", keys)
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// 31. 区间查询
+// FenwickTree（树状数组）支持单点修改和前缀和，代码短、常数小
+// SegmentTree（线段树）支持任意满足结合律的合并函数，以及惰性的区间赋值和区间加法
+// 两者的查询和修改都是O(log n)，区间均为左闭右开[l, r)
+
+// FenwickTree 树状数组，tree[i]保存区间(i-lowbit(i), i]的和（下标从1开始）
+type FenwickTree[T Real] struct {
+	tree []T
+}
+
+// NewFenwickTree 创建长度为n、元素全为0的树状数组
+func NewFenwickTree[T Real](n int) *FenwickTree[T] {
+	return &FenwickTree[T]{tree: make([]T, n+1)}
+}
+
+// FenwickTreeFrom 用values在O(n)时间内建树
+func FenwickTreeFrom[T Real](values []T) *FenwickTree[T] {
+	f := NewFenwickTree[T](len(values))
+	copy(f.tree[1:], values)
+	for i := 1; i < len(f.tree); i++ {
+		if parent := i + i&-i; parent < len(f.tree) {
+			f.tree[parent] += f.tree[i]
+		}
+	}
+	return f
+}
+
+// Len 返回元素个数
+func (f *FenwickTree[T]) Len() int {
+	return len(f.tree) - 1
+}
+
+// Add 将第i个元素加上delta，索引越界时返回false
+func (f *FenwickTree[T]) Add(i int, delta T) bool {
+	if i < 0 || i >= f.Len() {
+		return false
+	}
+	for i++; i < len(f.tree); i += i & -i {
+		f.tree[i] += delta
+	}
+	return true
+}
+
+// Set 将第i个元素设置为value，索引越界时返回false
+func (f *FenwickTree[T]) Set(i int, value T) bool {
+	current, ok := f.Get(i)
+	if !ok {
+		return false
+	}
+	return f.Add(i, value-current)
+}
+
+// Get 返回第i个元素
+func (f *FenwickTree[T]) Get(i int) (T, bool) {
+	if i < 0 || i >= f.Len() {
+		var zero T
+		return zero, false
+	}
+	return f.RangeSum(i, i+1), true
+}
+
+// PrefixSum 返回前n个元素的和，n超出范围时按边界截断
+func (f *FenwickTree[T]) PrefixSum(n int) T {
+	var sum T
+	for i := min(max(n, 0), f.Len()); i > 0; i -= i & -i {
+		sum += f.tree[i]
+	}
+	return sum
+}
+
+// RangeSum 返回区间[l, r)的和
+func (f *FenwickTree[T]) RangeSum(l, r int) T {
+	return f.PrefixSum(r) - f.PrefixSum(l)
+}
+
+// LowerBound 返回最小的i，使得前i+1个元素的和不小于target，要求所有元素非负
+// 常用于按权重抽样：target取[0, 总和)中的随机数
+func (f *FenwickTree[T]) LowerBound(target T) (int, bool) {
+	if f.Len() == 0 {
+		return 0, false
+	}
+	pos := 0
+	var sum T
+	for step := 1 << (bits.Len(uint(f.Len())) - 1); step > 0; step >>= 1 {
+		if next := pos + step; next < len(f.tree) && sum+f.tree[next] < target {
+			pos = next
+			sum += f.tree[next]
+		}
+	}
+	if pos >= f.Len() {
+		return 0, false
+	}
+	return pos, true
+}
+
+// SegmentTreeOps 线段树的运算
+type SegmentTreeOps[T any] struct {
+	// Combine 合并两个相邻区间的聚合值，必须满足结合律
+	Combine func(a, b T) T
+	// Add 可选，返回长度为n的区间每个元素加上delta后的聚合值，为nil时不支持AddRange
+	// 例如求和为 value+delta*n，最小值为 value+delta；n为1时用于叠加两次加法
+	Add func(value, delta T, n int) T
+}
+
+// SumOps 区间求和
+func SumOps[T Real]() SegmentTreeOps[T] {
+	return SegmentTreeOps[T]{
+		Combine: func(a, b T) T { return a + b },
+		Add:     func(value, delta T, n int) T { return value + delta*T(n) },
+	}
+}
+
+// MinOps 区间最小值
+func MinOps[T Real]() SegmentTreeOps[T] {
+	return SegmentTreeOps[T]{
+		Combine: func(a, b T) T { return min(a, b) },
+		Add:     func(value, delta T, _ int) T { return value + delta },
+	}
+}
+
+// MaxOps 区间最大值
+func MaxOps[T Real]() SegmentTreeOps[T] {
+	return SegmentTreeOps[T]{
+		Combine: func(a, b T) T { return max(a, b) },
+		Add:     func(value, delta T, _ int) T { return value + delta },
+	}
+}
+
+// GCDOps 区间最大公约数，不支持区间加法
+func GCDOps[T Integer]() SegmentTreeOps[T] {
+	return SegmentTreeOps[T]{Combine: gcd[T]}
+}
+
+// gcd 最大公约数，结果非负
+func gcd[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// segmentNode 线段树节点：聚合值和尚未下推的惰性标记
+// 两种标记同时存在时，含义是先整体赋值为assign，再整体加上add
+type segmentNode[T any] struct {
+	value     T
+	assign    T
+	add       T
+	hasAssign bool
+	hasAdd    bool
+}
+
+// SegmentTree 线段树
+type SegmentTree[T any] struct {
+	nodes []segmentNode[T] // nodes[1]为根，节点i的子节点为2i和2i+1
+	n     int
+	ops   SegmentTreeOps[T]
+}
+
+// NewSegmentTree 用values和运算ops建树
+func NewSegmentTree[T any](values []T, ops SegmentTreeOps[T]) *SegmentTree[T] {
+	t := &SegmentTree[T]{nodes: make([]segmentNode[T], 4*max(len(values), 1)), n: len(values), ops: ops}
+	if t.n > 0 {
+		t.build(1, 0, t.n, values)
+	}
+	return t
+}
+
+// build 递归建立节点i，它覆盖区间[lo, hi)
+func (t *SegmentTree[T]) build(i, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		t.nodes[i].value = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	t.build(2*i, lo, mid, values)
+	t.build(2*i+1, mid, hi, values)
+	t.nodes[i].value = t.ops.Combine(t.nodes[2*i].value, t.nodes[2*i+1].value)
+}
+
+// Len 返回元素个数
+func (t *SegmentTree[T]) Len() int {
+	return t.n
+}
+
+// repeat 返回n个v合并的结果，用倍增只需O(log n)次Combine
+func (t *SegmentTree[T]) repeat(v T, n int) T {
+	result, power := v, v
+	for n--; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result = t.ops.Combine(result, power)
+		}
+		power = t.ops.Combine(power, power)
+	}
+	return result
+}
+
+// applyAssign 将覆盖n个元素的节点i整体赋值为v
+func (t *SegmentTree[T]) applyAssign(i, n int, v T) {
+	node := &t.nodes[i]
+	node.value = t.repeat(v, n)
+	node.assign, node.hasAssign = v, true
+	var zero T
+	node.add, node.hasAdd = zero, false
+}
+
+// applyAdd 将覆盖n个元素的节点i整体加上delta
+func (t *SegmentTree[T]) applyAdd(i, n int, delta T) {
+	node := &t.nodes[i]
+	node.value = t.ops.Add(node.value, delta, n)
+	switch {
+	case node.hasAssign:
+		// 赋值后再加等价于赋值为加过的值
+		node.assign = t.ops.Add(node.assign, delta, 1)
+	case node.hasAdd:
+		node.add = t.ops.Add(node.add, delta, 1)
+	default:
+		node.add, node.hasAdd = delta, true
+	}
+}
+
+// pushDown 将节点i的惰性标记下推给两个子节点
+func (t *SegmentTree[T]) pushDown(i, lo, hi int) {
+	node := &t.nodes[i]
+	mid := (lo + hi) / 2
+	if node.hasAssign {
+		t.applyAssign(2*i, mid-lo, node.assign)
+		t.applyAssign(2*i+1, hi-mid, node.assign)
+		node.hasAssign = false
+	}
+	if node.hasAdd {
+		t.applyAdd(2*i, mid-lo, node.add)
+		t.applyAdd(2*i+1, hi-mid, node.add)
+		node.hasAdd = false
+	}
+}
+
+// update 对[l, r)与节点i的区间[lo, hi)的交集执行apply
+func (t *SegmentTree[T]) update(i, lo, hi, l, r int, apply func(i, n int)) {
+	if r <= lo || hi <= l {
+		return
+	}
+	if l <= lo && hi <= r {
+		apply(i, hi-lo)
+		return
+	}
+	t.pushDown(i, lo, hi)
+	mid := (lo + hi) / 2
+	t.update(2*i, lo, mid, l, r, apply)
+	t.update(2*i+1, mid, hi, l, r, apply)
+	t.nodes[i].value = t.ops.Combine(t.nodes[2*i].value, t.nodes[2*i+1].value)
+}
+
+// query 返回[l, r)与节点i的区间[lo, hi)的交集的聚合值，交集为空时返回false
+func (t *SegmentTree[T]) query(i, lo, hi, l, r int) (T, bool) {
+	if r <= lo || hi <= l {
+		var zero T
+		return zero, false
+	}
+	if l <= lo && hi <= r {
+		return t.nodes[i].value, true
+	}
+	t.pushDown(i, lo, hi)
+	mid := (lo + hi) / 2
+	left, okLeft := t.query(2*i, lo, mid, l, r)
+	right, okRight := t.query(2*i+1, mid, hi, l, r)
+	switch {
+	case okLeft && okRight:
+		return t.ops.Combine(left, right), true
+	case okLeft:
+		return left, true
+	default:
+		return right, okRight
+	}
+}
+
+// validRange 判断[l, r)是否为非空的合法区间
+func (t *SegmentTree[T]) validRange(l, r int) bool {
+	return 0 <= l && l < r && r <= t.n
+}
+
+// Query 返回区间[l, r)的聚合值，区间为空或越界时返回false
+func (t *SegmentTree[T]) Query(l, r int) (T, bool) {
+	if !t.validRange(l, r) {
+		var zero T
+		return zero, false
+	}
+	return t.query(1, 0, t.n, l, r)
+}
+
+// Get 返回第i个元素
+func (t *SegmentTree[T]) Get(i int) (T, bool) {
+	return t.Query(i, i+1)
+}
+
+// Set 将第i个元素设置为value，索引越界时返回false
+func (t *SegmentTree[T]) Set(i int, value T) bool {
+	return t.Assign(i, i+1, value)
+}
+
+// Assign 将区间[l, r)的每个元素赋值为value，区间为空或越界时返回false
+func (t *SegmentTree[T]) Assign(l, r int, value T) bool {
+	if !t.validRange(l, r) {
+		return false
+	}
+	t.update(1, 0, t.n, l, r, func(i, n int) { t.applyAssign(i, n, value) })
+	return true
+}
+
+// AddRange 将区间[l, r)的每个元素加上delta，区间为空或越界时返回false
+// 运算没有提供Add时panic
+func (t *SegmentTree[T]) AddRange(l, r int, delta T) bool {
+	if t.ops.Add == nil {
+		panic("SegmentTree: 运算不支持区间加法")
+	}
+	if !t.validRange(l, r) {
+		return false
+	}
+	t.update(1, 0, t.n, l, r, func(i, n int) { t.applyAdd(i, n, delta) })
+	return true
+}
+
+// 31.1 区间查询演示
+func demonstrateRangeQueries() {
+	fmt.Println("\n=== 区间查询演示 ===")
+
+	// 按小时分桶的请求计数，滚动统计最近6小时
+	hourly := []int{120, 98, 75, 60, 82, 150, 310, 420, 390, 280, 260, 300}
+	counts := FenwickTreeFrom(hourly)
+	fmt.Printf("每小时请求数: %v\n", hourly)
+	for end := 6; end <= len(hourly); end += 3 {
+		fmt.Printf("  [%2d, %2d)小时合计: %d\n", end-6, end, counts.RangeSum(end-6, end))
+	}
+	counts.Add(7, 80) // 补记第7小时延迟上报的请求
+	counts.Set(0, 0)  // 第0小时的数据作废
+	h7, _ := counts.Get(7)
+	fmt.Printf("修正后前8小时合计: %d, 第7小时: %d\n", counts.PrefixSum(8), h7)
+	total := counts.PrefixSum(counts.Len())
+	half, _ := counts.LowerBound(total / 2)
+	fmt.Printf("全天%d次请求, 到第%d小时累计过半\n", total, half)
+
+	// 线段树：区间最大值，并对一段时间整体加上偏移
+	latency := []float64{12, 15, 11, 40, 13, 14, 90, 12}
+	peak := NewSegmentTree(latency, MaxOps[float64]())
+	p, _ := peak.Query(0, 4)
+	fmt.Printf("延迟 %v\n  [0, 4)峰值: %.0fms\n", latency, p)
+	peak.AddRange(2, 5, 30) // 这三个时段叠加了30ms网络抖动
+	p, _ = peak.Query(0, 4)
+	fmt.Printf("  [2, 5)加上30ms后[0, 4)峰值: %.0fms\n", p)
+
+	// 区间求和 + 区间赋值 + 区间加法
+	sums := NewSegmentTree(make([]int, 10), SumOps[int]())
+	sums.Assign(0, 10, 5)
+	sums.AddRange(3, 7, 2)
+	sums.Set(9, 100)
+	s, _ := sums.Query(0, 10)
+	mid, _ := sums.Query(3, 7)
+	fmt.Printf("全部赋值为5, [3, 7)加2, 第9个设为100: 总和=%d, [3, 7)=%d\n", s, mid)
+
+	// 区间最小值和最大公约数
+	lows := NewSegmentTree([]int{5, 3, 8, 6, 2, 7}, MinOps[int]())
+	low, _ := lows.Query(2, 5)
+	gcds := NewSegmentTree([]int{12, 18, 24, 36, 9}, GCDOps[int]())
+	g1, _ := gcds.Query(0, 4)
+	g2, _ := gcds.Query(0, 5)
+	gcds.Assign(4, 5, 48)
+	g3, _ := gcds.Query(0, 5)
+	fmt.Printf("[2, 5)最小值=%d, gcd[0, 4)=%d, gcd[0, 5)=%d, 末尾改为48后gcd=%d\n", low, g1, g2, g3)
+}
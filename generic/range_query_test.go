@@ -0,0 +1,220 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFenwickTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 70; n++ {
+		ref := make([]int, n)
+		for i := range ref {
+			ref[i] = r.Intn(10)
+		}
+		f := FenwickTreeFrom(ref)
+		for range 300 {
+			if n > 0 && r.Intn(2) == 0 {
+				i := r.Intn(n)
+				if r.Intn(2) == 0 {
+					d := r.Intn(5)
+					f.Add(i, d)
+					ref[i] += d
+				} else {
+					v := r.Intn(9)
+					f.Set(i, v)
+					ref[i] = v
+				}
+			}
+
+			l := r.Intn(n + 1)
+			hi := l + r.Intn(n-l+1)
+			want := 0
+			for _, x := range ref[l:hi] {
+				want += x
+			}
+			if got := f.RangeSum(l, hi); got != want {
+				t.Fatalf("n=%d: RangeSum(%d, %d) = %d, want %d", n, l, hi, got, want)
+			}
+
+			total := 0
+			for _, x := range ref {
+				total += x
+			}
+			// target可能超过总和，此时没有满足条件的位置
+			target := r.Intn(total + 3)
+			wantIdx, sum := -1, 0
+			for i, x := range ref {
+				sum += x
+				if sum >= target {
+					wantIdx = i
+					break
+				}
+			}
+			got, ok := f.LowerBound(target)
+			if ok != (wantIdx >= 0) || ok && got != wantIdx {
+				t.Fatalf("n=%d: LowerBound(%d) = %d, %v, want %d", n, target, got, ok, wantIdx)
+			}
+		}
+		for i, want := range ref {
+			if got, ok := f.Get(i); !ok || got != want {
+				t.Fatalf("n=%d: Get(%d) = %d, %v, want %d", n, i, got, ok, want)
+			}
+		}
+	}
+}
+
+func TestFenwickTreeBounds(t *testing.T) {
+	f := NewFenwickTree[float64](4)
+	if f.Add(-1, 1) || f.Add(4, 1) || f.Set(4, 1) {
+		t.Error("out-of-range update succeeded")
+	}
+	if _, ok := f.Get(4); ok {
+		t.Error("Get(4) succeeded on a tree of length 4")
+	}
+	f.Set(1, 2.5)
+	f.Add(3, 0.5)
+	if got := f.PrefixSum(-1); got != 0 {
+		t.Errorf("PrefixSum(-1) = %v, want 0", got)
+	}
+	if got := f.PrefixSum(100); got != 3 {
+		t.Errorf("PrefixSum(100) = %v, want 3", got)
+	}
+	if got, ok := f.LowerBound(0); !ok || got != 0 {
+		t.Errorf("LowerBound(0) = %d, %v, want 0", got, ok)
+	}
+	if got, ok := f.LowerBound(2.6); !ok || got != 3 {
+		t.Errorf("LowerBound(2.6) = %d, %v, want 3", got, ok)
+	}
+	if _, ok := NewFenwickTree[int](0).LowerBound(0); ok {
+		t.Error("LowerBound on an empty tree succeeded")
+	}
+}
+
+func TestSegmentTree(t *testing.T) {
+	tests := []struct {
+		name      string
+		ops       SegmentTreeOps[int]
+		aggregate func([]int) int
+	}{
+		{"求和", SumOps[int](), func(a []int) int {
+			s := 0
+			for _, x := range a {
+				s += x
+			}
+			return s
+		}},
+		{"最小值", MinOps[int](), slices.Min[[]int]},
+		{"最大值", MaxOps[int](), slices.Max[[]int]},
+		{"最大公约数", GCDOps[int](), func(a []int) int {
+			// 与线段树一样依次合并，单个元素的区间返回元素本身
+			g := a[0]
+			for _, x := range a[1:] {
+				g = gcd(g, x)
+			}
+			return g
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for n := 1; n < 40; n++ {
+				ref := make([]int, n)
+				for i := range ref {
+					ref[i] = r.Intn(50) - 10
+				}
+				st := NewSegmentTree(append([]int(nil), ref...), tt.ops)
+				for range 300 {
+					l := r.Intn(n)
+					hi := l + 1 + r.Intn(n-l)
+					// 赋值和加法交替作用在相同的区间上，覆盖两种惰性标记的叠加
+					switch r.Intn(4) {
+					case 0:
+						v := r.Intn(30) - 5
+						st.Assign(l, hi, v)
+						for i := l; i < hi; i++ {
+							ref[i] = v
+						}
+					case 1:
+						if tt.ops.Add == nil {
+							continue
+						}
+						d := r.Intn(9) - 4
+						st.AddRange(l, hi, d)
+						for i := l; i < hi; i++ {
+							ref[i] += d
+						}
+					case 2:
+						i, v := r.Intn(n), r.Intn(20)
+						st.Set(i, v)
+						ref[i] = v
+					case 3:
+						got, ok := st.Query(l, hi)
+						if want := tt.aggregate(ref[l:hi]); !ok || got != want {
+							t.Fatalf("n=%d: Query(%d, %d) = %d, %v, want %d", n, l, hi, got, ok, want)
+						}
+					}
+				}
+				for i, want := range ref {
+					if got, ok := st.Get(i); !ok || got != want {
+						t.Fatalf("n=%d: Get(%d) = %d, %v, want %d", n, i, got, ok, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSegmentTreeNonCommutative(t *testing.T) {
+	// 字符串拼接满足结合律但不满足交换律，可以检查合并顺序和区间赋值的倍增
+	concat := SegmentTreeOps[string]{Combine: func(a, b string) string { return a + b }}
+	ref := strings.Split("abcdefghij", "")
+	st := NewSegmentTree(append([]string(nil), ref...), concat)
+	r := rand.New(rand.NewSource(1))
+	for range 500 {
+		l := r.Intn(len(ref))
+		hi := l + 1 + r.Intn(len(ref)-l)
+		if r.Intn(3) == 0 {
+			v := string(rune('A' + r.Intn(26)))
+			st.Assign(l, hi, v)
+			for i := l; i < hi; i++ {
+				ref[i] = v
+			}
+		}
+		if got, _ := st.Query(l, hi); got != strings.Join(ref[l:hi], "") {
+			t.Fatalf("Query(%d, %d) = %q, want %q", l, hi, got, strings.Join(ref[l:hi], ""))
+		}
+	}
+}
+
+func TestSegmentTreeBounds(t *testing.T) {
+	empty := NewSegmentTree(nil, SumOps[int]())
+	if _, ok := empty.Query(0, 0); ok || empty.Len() != 0 {
+		t.Error("Query on an empty tree succeeded")
+	}
+
+	st := NewSegmentTree([]int{1, 2, 3}, SumOps[int]())
+	for _, rng := range [][2]int{{-1, 2}, {2, 2}, {2, 1}, {0, 4}} {
+		if _, ok := st.Query(rng[0], rng[1]); ok {
+			t.Errorf("Query(%d, %d) succeeded", rng[0], rng[1])
+		}
+		if st.Assign(rng[0], rng[1], 0) || st.AddRange(rng[0], rng[1], 1) {
+			t.Errorf("update of [%d, %d) succeeded", rng[0], rng[1])
+		}
+	}
+	if st.Set(3, 0) {
+		t.Error("Set(3) succeeded on a tree of length 3")
+	}
+	if got, _ := st.Query(0, 3); got != 6 {
+		t.Errorf("invalid updates changed the tree: sum = %d", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("AddRange with GCDOps did not panic")
+		}
+	}()
+	NewSegmentTree([]int{4, 6}, GCDOps[int]()).AddRange(0, 2, 1)
+}
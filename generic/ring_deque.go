@@ -0,0 +1,394 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// 26. 环形缓冲区与双端队列
+// Ring 容量固定，写满后按策略覆盖最旧的元素或拒绝写入，适合滑动窗口和有界事件缓冲
+// Deque 基于可增长的环形数组，两端的Push/Pop均摊O(1)，支持按索引访问
+
+// OverflowPolicy 环形缓冲区写满后的处理策略
+type OverflowPolicy int
+
+const (
+	Overwrite OverflowPolicy = iota // 覆盖最旧的元素
+	Reject                          // 拒绝写入新元素
+)
+
+// ErrRingFull 环形缓冲区已满且策略为Reject时Push返回的错误
+var ErrRingFull = errors.New("环形缓冲区已满")
+
+// Ring 固定容量的环形缓冲区，索引0是最旧的元素，请使用NewRing创建，零值只能用于反序列化
+type Ring[T any] struct {
+	buf     []T
+	head    int // 最旧元素的位置
+	size    int
+	policy  OverflowPolicy
+	dropped uint64 // 被覆盖或被拒绝的元素个数
+}
+
+// NewRing 创建容量为capacity的环形缓冲区
+func NewRing[T any](capacity int, policy OverflowPolicy) *Ring[T] {
+	if capacity < 1 {
+		panic("NewRing: 容量必须大于0")
+	}
+	return &Ring[T]{buf: make([]T, capacity), policy: policy}
+}
+
+// Len 返回元素个数
+func (r *Ring[T]) Len() int {
+	return r.size
+}
+
+// Cap 返回容量
+func (r *Ring[T]) Cap() int {
+	return len(r.buf)
+}
+
+// IsEmpty 判断是否为空
+func (r *Ring[T]) IsEmpty() bool {
+	return r.size == 0
+}
+
+// IsFull 判断是否已满
+func (r *Ring[T]) IsFull() bool {
+	return r.size == len(r.buf)
+}
+
+// Dropped 返回因缓冲区已满而被覆盖或被拒绝的元素个数
+func (r *Ring[T]) Dropped() uint64 {
+	return r.dropped
+}
+
+// Push 在尾部写入元素
+// 已满时Overwrite策略覆盖最旧的元素，Reject策略返回ErrRingFull，容量为0的零值会panic
+func (r *Ring[T]) Push(value T) error {
+	if len(r.buf) == 0 {
+		panic("Ring: 容量为0，请使用NewRing创建")
+	}
+	if r.size == len(r.buf) {
+		r.dropped++
+		if r.policy == Reject {
+			return ErrRingFull
+		}
+		r.buf[r.head] = value
+		r.head = (r.head + 1) % len(r.buf)
+		return nil
+	}
+	r.buf[(r.head+r.size)%len(r.buf)] = value
+	r.size++
+	return nil
+}
+
+// Pop 移除并返回最旧的元素
+func (r *Ring[T]) Pop() (T, bool) {
+	var zero T
+	if r.size == 0 {
+		return zero, false
+	}
+	value := r.buf[r.head]
+	r.buf[r.head] = zero
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+	return value, true
+}
+
+// Peek 返回最旧的元素
+func (r *Ring[T]) Peek() (T, bool) {
+	return r.Get(0)
+}
+
+// PeekBack 返回最新的元素
+func (r *Ring[T]) PeekBack() (T, bool) {
+	return r.Get(r.size - 1)
+}
+
+// Get 返回第index个元素，0是最旧的元素
+func (r *Ring[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= r.size {
+		var zero T
+		return zero, false
+	}
+	return r.buf[(r.head+index)%len(r.buf)], true
+}
+
+// Clear 清空缓冲区，丢弃计数保持不变
+func (r *Ring[T]) Clear() {
+	clear(r.buf)
+	r.head, r.size = 0, 0
+}
+
+// All 从旧到新遍历索引和元素
+func (r *Ring[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < r.size; i++ {
+			if !yield(i, r.buf[(r.head+i)%len(r.buf)]) {
+				return
+			}
+		}
+	}
+}
+
+// Values 从旧到新遍历元素
+func (r *Ring[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range r.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 按从旧到新的顺序转换为切片
+func (r *Ring[T]) ToSlice() []T {
+	result := make([]T, 0, r.size)
+	for _, v := range r.All() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// minDequeCap 双端队列的最小容量，容量始终是2的幂，便于用位运算取模
+const minDequeCap = 8
+
+// Deque 双端队列，零值可以直接使用
+type Deque[T any] struct {
+	buf    []T
+	head   int // 第一个元素的位置
+	size   int
+	minCap int // 缩容的下限，即创建时预分配的容量
+}
+
+// NewDeque 创建双端队列，capacity为预分配的容量，出队缩容时容量不会低于它
+func NewDeque[T any](capacity int) *Deque[T] {
+	c := minDequeCap
+	for c < capacity {
+		c <<= 1
+	}
+	return &Deque[T]{buf: make([]T, c), minCap: c}
+}
+
+// Len 返回元素个数
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+// IsEmpty 判断是否为空
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+// index 返回第i个元素在buf中的位置
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+// resize 将元素按顺序搬到容量为capacity的新数组
+func (d *Deque[T]) resize(capacity int) {
+	buf := make([]T, capacity)
+	if d.size > 0 {
+		n := copy(buf, d.buf[d.head:min(d.head+d.size, len(d.buf))])
+		copy(buf[n:], d.buf[:d.size-n])
+	}
+	d.buf, d.head = buf, 0
+}
+
+// grow 已满时容量翻倍
+func (d *Deque[T]) grow() {
+	if d.size == len(d.buf) {
+		d.resize(max(minDequeCap, 2*len(d.buf)))
+	}
+}
+
+// shrink 元素不足容量的1/4时容量减半，避免大量出队后长期占用内存
+// 容量不会低于预分配的容量，否则滑动窗口这类反复入队出队的场景每次都要重新分配
+func (d *Deque[T]) shrink() {
+	if len(d.buf) > max(minDequeCap, d.minCap) && d.size <= len(d.buf)/4 {
+		d.resize(len(d.buf) / 2)
+	}
+}
+
+// PushBack 在尾部添加元素
+func (d *Deque[T]) PushBack(value T) {
+	d.grow()
+	d.buf[d.index(d.size)] = value
+	d.size++
+}
+
+// PushFront 在头部添加元素
+func (d *Deque[T]) PushFront(value T) {
+	d.grow()
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = value
+	d.size++
+}
+
+// PopFront 移除并返回头部元素
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	value := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = d.index(1)
+	d.size--
+	d.shrink()
+	return value, true
+}
+
+// PopBack 移除并返回尾部元素
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	i := d.index(d.size - 1)
+	value := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+	d.shrink()
+	return value, true
+}
+
+// Front 返回头部元素
+func (d *Deque[T]) Front() (T, bool) {
+	return d.Get(0)
+}
+
+// Back 返回尾部元素
+func (d *Deque[T]) Back() (T, bool) {
+	return d.Get(d.size - 1)
+}
+
+// Get 返回第index个元素
+func (d *Deque[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= d.size {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.index(index)], true
+}
+
+// Set 修改第index个元素，索引越界时返回false
+func (d *Deque[T]) Set(index int, value T) bool {
+	if index < 0 || index >= d.size {
+		return false
+	}
+	d.buf[d.index(index)] = value
+	return true
+}
+
+// Clear 清空队列
+func (d *Deque[T]) Clear() {
+	clear(d.buf)
+	d.head, d.size = 0, 0
+}
+
+// All 从头到尾遍历索引和元素
+func (d *Deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(i, d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 从尾到头遍历索引和元素
+func (d *Deque[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := d.size - 1; i >= 0; i-- {
+			if !yield(i, d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Values 从头到尾遍历元素
+func (d *Deque[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range d.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 按从头到尾的顺序转换为切片
+func (d *Deque[T]) ToSlice() []T {
+	result := make([]T, 0, d.size)
+	for _, v := range d.All() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// 26.1 环形缓冲区与双端队列演示
+func demonstrateRingDeque() {
+	fmt.Println("\n=== 环形缓冲区与双端队列演示 ===")
+
+	// 滑动窗口：最近5次请求耗时的移动平均
+	window := NewRing[float64](5, Overwrite)
+	for _, latency := range []float64{12, 15, 11, 40, 13, 14, 90, 12} {
+		window.Push(latency)
+		avg, _ := Mean(window.ToSlice())
+		fmt.Printf("耗时 %4.0fms 窗口%v 移动平均 %.1fms\n", latency, window.ToSlice(), avg)
+	}
+	fmt.Printf("被挤出窗口的样本数: %d\n", window.Dropped())
+
+	// 有界事件缓冲：写满后拒绝新事件
+	events := NewRing[string](3, Reject)
+	for _, e := range []string{"登录", "下单", "支付", "退出"} {
+		if err := events.Push(e); err != nil {
+			fmt.Printf("事件 %q 被丢弃: %v\n", e, err)
+		}
+	}
+	oldest, _ := events.Pop()
+	fmt.Printf("最旧事件: %s, 剩余: %v\n", oldest, events.ToSlice())
+
+	// 双端队列：两端操作和按索引访问
+	var dq Deque[int]
+	for i := 1; i <= 5; i++ {
+		dq.PushBack(i)
+		dq.PushFront(-i)
+	}
+	fmt.Printf("双端队列: %v (长度%d)\n", dq.ToSlice(), dq.Len())
+	front, _ := dq.PopFront()
+	back, _ := dq.PopBack()
+	third, _ := dq.Get(2)
+	fmt.Printf("PopFront=%d PopBack=%d Get(2)=%d\n", front, back, third)
+
+	fmt.Print("反向遍历: ")
+	for _, v := range dq.Backward() {
+		fmt.Printf("%d ", v)
+	}
+	fmt.Println()
+
+	// 用双端队列求滑动窗口最大值：队列中保存单调递减的下标
+	nums := []int{1, 3, -1, -3, 5, 3, 6, 7}
+	k := 3
+	var idx Deque[int]
+	var maxes []int
+	for i, n := range nums {
+		for back, ok := idx.Back(); ok && nums[back] <= n; back, ok = idx.Back() {
+			idx.PopBack()
+		}
+		idx.PushBack(i)
+		if front, _ := idx.Front(); front <= i-k {
+			idx.PopFront()
+		}
+		if i >= k-1 {
+			front, _ := idx.Front()
+			maxes = append(maxes, nums[front])
+		}
+	}
+	fmt.Printf("%v 中大小为%d的滑动窗口最大值: %v\n", nums, k, maxes)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRingPolicies(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, policy := range []OverflowPolicy{Overwrite, Reject} {
+		ring := NewRing[int](7, policy)
+		var ref []int
+		var dropped uint64
+		for i := 0; i < 20000; i++ {
+			if r.Intn(3) > 0 {
+				err := ring.Push(i)
+				switch {
+				case len(ref) < 7:
+					ref = append(ref, i)
+				case policy == Reject:
+					if !errors.Is(err, ErrRingFull) {
+						t.Fatalf("Push on full Reject ring = %v", err)
+					}
+					dropped++
+				default:
+					ref = append(ref[1:], i)
+					dropped++
+				}
+			} else {
+				v, ok := ring.Pop()
+				if ok != (len(ref) > 0) || ok && v != ref[0] {
+					t.Fatalf("Pop() = %d, %v, want %v", v, ok, ref)
+				}
+				if ok {
+					ref = ref[1:]
+				}
+			}
+			if got := ring.ToSlice(); !slices.Equal(got, ref) {
+				t.Fatalf("policy %d: contents %v, want %v", policy, got, ref)
+			}
+			if ring.IsFull() != (len(ref) == 7) || ring.IsEmpty() != (len(ref) == 0) {
+				t.Fatalf("IsFull/IsEmpty wrong with %d elements", len(ref))
+			}
+		}
+		if ring.Dropped() != dropped {
+			t.Errorf("policy %d: Dropped() = %d, want %d", policy, ring.Dropped(), dropped)
+		}
+	}
+}
+
+func TestRingAccessors(t *testing.T) {
+	ring := NewRing[string](3, Overwrite)
+	if _, ok := ring.Peek(); ok {
+		t.Fatal("Peek() on empty ring reported a value")
+	}
+	for _, s := range []string{"a", "b", "c", "d"} {
+		ring.Push(s)
+	}
+	if v, _ := ring.Peek(); v != "b" {
+		t.Errorf("Peek() = %q, want b", v)
+	}
+	if v, _ := ring.PeekBack(); v != "d" {
+		t.Errorf("PeekBack() = %q, want d", v)
+	}
+	if _, ok := ring.Get(3); ok {
+		t.Error("Get(3) succeeded on a ring of length 3")
+	}
+	if _, ok := ring.Get(-1); ok {
+		t.Error("Get(-1) succeeded")
+	}
+	for i, v := range ring.All() {
+		if want, _ := ring.Get(i); v != want {
+			t.Errorf("All() yielded %d=%q, want %q", i, v, want)
+		}
+	}
+	var first []string
+	for v := range ring.Values() {
+		first = append(first, v)
+		break
+	}
+	if !slices.Equal(first, []string{"b"}) {
+		t.Errorf("Values() with break = %v", first)
+	}
+
+	ring.Clear()
+	if ring.Len() != 0 || ring.Cap() != 3 || ring.Dropped() != 1 {
+		t.Errorf("after Clear: Len=%d Cap=%d Dropped=%d", ring.Len(), ring.Cap(), ring.Dropped())
+	}
+	ring.Push("e")
+	if got := ring.ToSlice(); !slices.Equal(got, []string{"e"}) {
+		t.Errorf("Push after Clear = %v", got)
+	}
+
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"NewRing(0)", func() { NewRing[int](0, Overwrite) }},
+		{"零值Push", func() { new(Ring[int]).Push(1) }},
+		{"零值Reject策略Push", func() { (&Ring[int]{policy: Reject}).Push(1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "NewRing") {
+					t.Errorf("%s: recovered %v, want a panic mentioning NewRing", tt.name, r)
+				}
+			}()
+			tt.call()
+		})
+	}
+}
+
+func TestDequeRandomOps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var d Deque[int]
+	var ref []int
+	for i := 0; i < 50000; i++ {
+		// 前半段以入队为主让容量增长，后半段以出队为主触发缩容
+		pushBias := 3
+		if i > 25000 {
+			pushBias = 1
+		}
+		switch op := r.Intn(pushBias + 3); {
+		case op < pushBias && r.Intn(2) == 0:
+			d.PushBack(i)
+			ref = append(ref, i)
+		case op < pushBias:
+			d.PushFront(i)
+			ref = slices.Insert(ref, 0, i)
+		case op == pushBias:
+			v, ok := d.PopFront()
+			if ok != (len(ref) > 0) || ok && v != ref[0] {
+				t.Fatalf("PopFront() = %d, %v", v, ok)
+			}
+			if ok {
+				ref = ref[1:]
+			}
+		case op == pushBias+1:
+			v, ok := d.PopBack()
+			if ok != (len(ref) > 0) || ok && v != ref[len(ref)-1] {
+				t.Fatalf("PopBack() = %d, %v", v, ok)
+			}
+			if ok {
+				ref = ref[:len(ref)-1]
+			}
+		default:
+			if len(ref) > 0 {
+				j := r.Intn(len(ref))
+				if !d.Set(j, -i) {
+					t.Fatalf("Set(%d) failed", j)
+				}
+				ref[j] = -i
+			}
+		}
+		if d.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", d.Len(), len(ref))
+		}
+		if len(d.buf) > minDequeCap && d.size < len(d.buf)/4 {
+			t.Fatalf("capacity %d not shrunk with %d elements", len(d.buf), d.size)
+		}
+		if i%500 == 0 && !slices.Equal(d.ToSlice(), ref) {
+			t.Fatalf("contents differ after %d operations", i)
+		}
+	}
+	if !slices.Equal(d.ToSlice(), ref) {
+		t.Fatal("final contents differ")
+	}
+}
+
+func TestDequePreallocation(t *testing.T) {
+	// 预分配的容量在反复入队出队时保持不变
+	d := NewDeque[int](1000)
+	for i := range 10000 {
+		d.PushBack(i)
+		if i%3 == 0 {
+			d.PopFront()
+		} else {
+			d.PopBack()
+		}
+		if len(d.buf) != 1024 {
+			t.Fatalf("after %d push/pop pairs: capacity %d, want 1024", i+1, len(d.buf))
+		}
+	}
+
+	// 超出预分配的部分在出队后收回，但不会低于预分配的容量
+	for i := range 5000 {
+		d.PushFront(i)
+	}
+	if len(d.buf) != 8192 {
+		t.Fatalf("capacity %d with 5000 elements, want 8192", len(d.buf))
+	}
+	for !d.IsEmpty() {
+		d.PopBack()
+	}
+	if len(d.buf) != 1024 {
+		t.Errorf("capacity %d after popping everything, want 1024", len(d.buf))
+	}
+
+	// 反序列化替换内容时也保留预分配的容量
+	if err := d.UnmarshalJSON([]byte("[1,2,3]")); err != nil {
+		t.Fatal(err)
+	}
+	d.PopFront()
+	if len(d.buf) != 1024 || d.Len() != 2 {
+		t.Errorf("after decoding: capacity %d, Len() = %d", len(d.buf), d.Len())
+	}
+}
+
+func TestDequeAccessors(t *testing.T) {
+	d := NewDeque[int](10)
+	if len(d.buf) != 16 {
+		t.Errorf("NewDeque(10) capacity = %d, want 16", len(d.buf))
+	}
+	if _, ok := d.Front(); ok {
+		t.Error("Front() on empty deque reported a value")
+	}
+	if d.Set(0, 1) {
+		t.Error("Set(0) succeeded on empty deque")
+	}
+	for i := 1; i <= 5; i++ {
+		d.PushBack(i)
+	}
+	d.PushFront(0)
+	if f, _ := d.Front(); f != 0 {
+		t.Errorf("Front() = %d", f)
+	}
+	if b, _ := d.Back(); b != 5 {
+		t.Errorf("Back() = %d", b)
+	}
+	var backward []int
+	for i, v := range d.Backward() {
+		if got, _ := d.Get(i); got != v {
+			t.Errorf("Backward() yielded %d=%d, want %d", i, v, got)
+		}
+		backward = append(backward, v)
+	}
+	if !slices.Equal(backward, []int{5, 4, 3, 2, 1, 0}) {
+		t.Errorf("Backward() = %v", backward)
+	}
+	if got := slices.Collect(d.Values()); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Values() = %v", got)
+	}
+
+	d.Clear()
+	if !d.IsEmpty() {
+		t.Error("Clear() did not empty the deque")
+	}
+	d.PushFront(9)
+	if got := d.ToSlice(); !slices.Equal(got, []int{9}) {
+		t.Errorf("PushFront after Clear = %v", got)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// 18. 集合
+// Set 基于 map[T]struct{} 实现，空结构体不占用内存
+// 零值Set可以直接使用
+
+// Set 泛型集合
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// NewSet 创建包含指定元素的集合
+func NewSet[T comparable](values ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(values))}
+	s.Add(values...)
+	return s
+}
+
+// Add 添加元素
+func (s *Set[T]) Add(values ...T) {
+	if s.items == nil {
+		s.items = make(map[T]struct{}, len(values))
+	}
+	for _, v := range values {
+		s.items[v] = struct{}{}
+	}
+}
+
+// Remove 删除元素，返回元素是否存在
+func (s *Set[T]) Remove(value T) bool {
+	if _, ok := s.items[value]; !ok {
+		return false
+	}
+	delete(s.items, value)
+	return true
+}
+
+// Contains 检查元素是否存在
+func (s *Set[T]) Contains(value T) bool {
+	_, ok := s.items[value]
+	return ok
+}
+
+// Size 返回集合中元素的数量
+func (s *Set[T]) Size() int {
+	return len(s.items)
+}
+
+// IsEmpty 检查集合是否为空
+func (s *Set[T]) IsEmpty() bool {
+	return len(s.items) == 0
+}
+
+// Clear 清空集合
+func (s *Set[T]) Clear() {
+	clear(s.items)
+}
+
+// Clone 返回集合的副本
+func (s *Set[T]) Clone() *Set[T] {
+	result := &Set[T]{items: make(map[T]struct{}, len(s.items))}
+	maps.Copy(result.items, s.items)
+	return result
+}
+
+// All 遍历集合中的元素，顺序不确定
+func (s *Set[T]) All() iter.Seq[T] {
+	return maps.Keys(s.items)
+}
+
+// ToSlice 返回包含所有元素的切片，顺序不确定
+func (s *Set[T]) ToSlice() []T {
+	return slices.AppendSeq(make([]T, 0, len(s.items)), s.All())
+}
+
+// Union 并集：属于s或other的元素
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := s.Clone()
+	maps.Copy(result.items, other.items)
+	return result
+}
+
+// Intersection 交集：同时属于s和other的元素
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	// 遍历较小的集合
+	small, large := s, other
+	if small.Size() > large.Size() {
+		small, large = large, small
+	}
+	result := NewSet[T]()
+	for v := range small.items {
+		if large.Contains(v) {
+			result.items[v] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference 差集：属于s但不属于other的元素
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for v := range s.items {
+		if !other.Contains(v) {
+			result.items[v] = struct{}{}
+		}
+	}
+	return result
+}
+
+// SymmetricDifference 对称差：只属于其中一个集合的元素
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	result := s.Difference(other)
+	for v := range other.items {
+		if !s.Contains(v) {
+			result.items[v] = struct{}{}
+		}
+	}
+	return result
+}
+
+// IsSubset 检查s是否为other的子集
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	for v := range s.items {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset 检查s是否为other的超集
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal 检查两个集合是否包含相同的元素
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Size() == other.Size() && s.IsSubset(other)
+}
+
+// String 输出集合的所有元素，元素顺序不确定，每次调用的结果可能不同
+func (s *Set[T]) String() string {
+	return fmt.Sprintf("Set%v", s.ToSlice())
+}
+
+// MarshalJSON 将集合序列化为JSON数组
+// 使用值接收者，使得结构体中的Set字段无论是否可寻址都能正确序列化
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+// UnmarshalJSON 从JSON数组反序列化集合，重复的元素会被合并，null不做任何修改
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	s.items = make(map[T]struct{}, len(values))
+	s.Add(values...)
+	return nil
+}
+
+// SortedValues 按升序返回集合中的元素
+// 方法不能额外约束类型参数，因此排序输出以函数形式提供
+func SortedValues[T Ordered](s *Set[T]) []T {
+	return slices.Sorted(s.All())
+}
+
+// 18.1 集合演示
+func demonstrateSet() {
+	fmt.Println("\n=== 集合演示 ===")
+
+	backend := NewSet("Go", "Java", "Python", "Rust")
+	frontend := NewSet("JavaScript", "TypeScript", "Python")
+
+	fmt.Printf("后端语言: %v\n", SortedValues(backend))
+	fmt.Printf("前端语言: %v\n", SortedValues(frontend))
+	fmt.Printf("并集: %v\n", SortedValues(backend.Union(frontend)))
+	fmt.Printf("交集: %v\n", SortedValues(backend.Intersection(frontend)))
+	fmt.Printf("差集(后端-前端): %v\n", SortedValues(backend.Difference(frontend)))
+	fmt.Printf("对称差: %v\n", SortedValues(backend.SymmetricDifference(frontend)))
+
+	systems := NewSet("Go", "Rust")
+	fmt.Printf("%v 是后端语言的子集: %v\n", SortedValues(systems), systems.IsSubset(backend))
+
+	// 集合以JSON数组的形式写入文件并读回
+	type Team struct {
+		Name   string      `json:"name"`
+		Skills Set[string] `json:"skills"`
+	}
+
+	team := Team{Name: "基础架构组", Skills: *NewSet("Go", "Kubernetes", "Go")}
+	jsonData, err := json.Marshal(team)
+	if err != nil {
+		fmt.Printf("序列化JSON失败: %v\n", err)
+		return
+	}
+
+	path := filepath.Join(os.TempDir(), "team.json")
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		fmt.Printf("写入JSON文件失败: %v\n", err)
+		return
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("读取JSON文件失败: %v\n", err)
+		return
+	}
+
+	var loaded Team
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		fmt.Printf("反序列化JSON失败: %v\n", err)
+		return
+	}
+	fmt.Printf("从JSON文件加载: %s, 技能: %v\n", loaded.Name, SortedValues(&loaded.Skills))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	tests := []struct {
+		name                       string
+		a, b                       []int
+		union, inter, diff, symDif []int
+		subset, superset, equal    bool
+	}{
+		{"部分重叠", []int{1, 2, 3}, []int{2, 3, 4}, []int{1, 2, 3, 4}, []int{2, 3}, []int{1}, []int{1, 4}, false, false, false},
+		{"子集", []int{1, 2}, []int{1, 2, 3}, []int{1, 2, 3}, []int{1, 2}, nil, []int{3}, true, false, false},
+		{"相等", []int{1, 2}, []int{2, 1}, []int{1, 2}, []int{1, 2}, nil, nil, true, true, true},
+		{"不相交", []int{1}, []int{2}, []int{1, 2}, nil, []int{1}, []int{1, 2}, false, false, false},
+		{"空集", nil, []int{1}, []int{1}, nil, nil, []int{1}, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := NewSet(tt.a...), NewSet(tt.b...)
+			check := func(op string, got *Set[int], want []int) {
+				t.Helper()
+				if g := SortedValues(got); !slices.Equal(g, want) && !(len(g) == 0 && len(want) == 0) {
+					t.Errorf("%s = %v, want %v", op, g, want)
+				}
+			}
+			check("Union", a.Union(b), tt.union)
+			check("Intersection", a.Intersection(b), tt.inter)
+			check("Difference", a.Difference(b), tt.diff)
+			check("SymmetricDifference", a.SymmetricDifference(b), tt.symDif)
+			if a.IsSubset(b) != tt.subset || a.IsSuperset(b) != tt.superset || a.Equal(b) != tt.equal {
+				t.Errorf("IsSubset/IsSuperset/Equal = %v/%v/%v", a.IsSubset(b), a.IsSuperset(b), a.Equal(b))
+			}
+			// 运算不修改原集合
+			check("a", a, SortedValues(NewSet(tt.a...)))
+		})
+	}
+}
+
+func TestSetZeroValueAndClone(t *testing.T) {
+	var s Set[string]
+	if s.Contains("x") || s.Remove("x") || !s.IsEmpty() {
+		t.Fatal("zero Set is not empty")
+	}
+	s.Add("x", "y", "x")
+	if s.Size() != 2 {
+		t.Fatalf("Size() = %d, want 2", s.Size())
+	}
+	c := s.Clone()
+	c.Add("z")
+	if s.Contains("z") {
+		t.Fatal("Clone shares storage with the original")
+	}
+	s.Clear()
+	if !s.IsEmpty() || c.Size() != 3 {
+		t.Fatal("Clear affected the clone or did not empty the set")
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	type Team struct {
+		Name    string
+		Members Set[string]
+	}
+	in := Team{Name: "core", Members: *NewSet("alice", "bob")}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Team
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Members.Equal(&in.Members) {
+		t.Fatalf("round trip = %v, want %v", out.Members.ToSlice(), in.Members.ToSlice())
+	}
+
+	var dup Set[int]
+	if err := json.Unmarshal([]byte("[1,1,2]"), &dup); err != nil || dup.Size() != 2 {
+		t.Fatalf("duplicates not merged: %v, %v", dup.ToSlice(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"a":1}`), &dup); err == nil {
+		t.Fatal("unmarshaling an object succeeded")
+	}
+}
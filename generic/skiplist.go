@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// 27. 并发跳表
+// 读操作（Get、Contains、All、Range）不加锁，通过原子指针读取，可以和写操作同时进行
+// 写操作（Put、Delete）由互斥锁串行化，新节点先设置好后继再自底向上发布，读者不会看到未初始化的节点
+// 每层记录跨度（跨过的底层节点数），支持O(log n)的Rank/Select，这两个操作需要获取写锁以读到一致的跨度
+
+// maxSkipLevel 跳表的最大层数，足以容纳2^32个元素
+const maxSkipLevel = 32
+
+// skipNode 跳表节点
+type skipNode[K Ordered, V any] struct {
+	key   K
+	value atomic.Pointer[V]
+	next  []atomic.Pointer[skipNode[K, V]]
+	span  []int // span[i]为从该节点沿第i层走到next[i]跨过的底层节点数，受写锁保护
+}
+
+// SkipList 支持并发读的有序跳表，请使用NewSkipList创建，零值只能用于反序列化
+type SkipList[K Ordered, V any] struct {
+	mu    sync.Mutex // 串行化写操作
+	head  *skipNode[K, V]
+	level atomic.Int32 // 当前最高层数
+	size  atomic.Int64
+}
+
+// NewSkipList 创建跳表
+func NewSkipList[K Ordered, V any]() *SkipList[K, V] {
+	s := &SkipList[K, V]{}
+	s.initHead()
+	return s
+}
+
+// initHead 创建头节点，反序列化到零值跳表时也会调用
+func (s *SkipList[K, V]) initHead() {
+	s.head = &skipNode[K, V]{
+		next: make([]atomic.Pointer[skipNode[K, V]], maxSkipLevel),
+		span: make([]int, maxSkipLevel),
+	}
+	s.level.Store(1)
+}
+
+// randomLevel 随机生成新节点的层数，每升一层的概率为1/4
+func randomLevel() int {
+	level := 1
+	for level < maxSkipLevel && rand.Intn(4) == 0 {
+		level++
+	}
+	return level
+}
+
+// Size 返回元素个数
+func (s *SkipList[K, V]) Size() int {
+	return int(s.size.Load())
+}
+
+// IsEmpty 判断是否为空
+func (s *SkipList[K, V]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// findGE 返回第一个键大于等于key的节点，不加锁
+func (s *SkipList[K, V]) findGE(key K) *skipNode[K, V] {
+	x := s.head
+	for i := int(s.level.Load()) - 1; i >= 0; i-- {
+		for n := x.next[i].Load(); n != nil && n.key < key; n = x.next[i].Load() {
+			x = n
+		}
+	}
+	return x.next[0].Load()
+}
+
+// Get 查找键对应的值，不加锁
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	if n := s.findGE(key); n != nil && n.key == key {
+		return *n.value.Load(), true
+	}
+	var zero V
+	return zero, false
+}
+
+// Contains 判断键是否存在，不加锁
+func (s *SkipList[K, V]) Contains(key K) bool {
+	n := s.findGE(key)
+	return n != nil && n.key == key
+}
+
+// findPath 查找每一层最后一个键小于key的节点及其排名，调用方需持有写锁
+// rank[i]为update[i]的排名，头节点为0，第一个元素为1
+func (s *SkipList[K, V]) findPath(key K) (update [maxSkipLevel]*skipNode[K, V], rank [maxSkipLevel]int) {
+	x := s.head
+	r := 0
+	for i := int(s.level.Load()) - 1; i >= 0; i-- {
+		for n := x.next[i].Load(); n != nil && n.key < key; n = x.next[i].Load() {
+			r += x.span[i]
+			x = n
+		}
+		update[i], rank[i] = x, r
+	}
+	return update, rank
+}
+
+// Put 插入或更新键值
+func (s *SkipList[K, V]) Put(key K, value V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update, rank := s.findPath(key)
+	if n := update[0].next[0].Load(); n != nil && n.key == key {
+		n.value.Store(&value)
+		return
+	}
+
+	level := randomLevel()
+	current := int(s.level.Load())
+	for i := current; i < level; i++ {
+		update[i], rank[i] = s.head, 0
+		s.head.span[i] = s.Size()
+	}
+
+	x := &skipNode[K, V]{
+		key:  key,
+		next: make([]atomic.Pointer[skipNode[K, V]], level),
+		span: make([]int, level),
+	}
+	x.value.Store(&value)
+	for i := 0; i < level; i++ {
+		x.next[i].Store(update[i].next[i].Load())
+		x.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	// 自底向上发布：读者在高层看到新节点时，它在低层一定已经可达
+	for i := 0; i < level; i++ {
+		update[i].next[i].Store(x)
+	}
+	for i := level; i < current; i++ {
+		update[i].span[i]++
+	}
+	if level > current {
+		s.level.Store(int32(level))
+	}
+	s.size.Add(1)
+}
+
+// Delete 删除键，返回键是否存在
+func (s *SkipList[K, V]) Delete(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update, _ := s.findPath(key)
+	x := update[0].next[0].Load()
+	if x == nil || x.key != key {
+		return false
+	}
+	// 自顶向下摘除，正在x上的读者仍可以沿x的后继继续前进
+	for i := int(s.level.Load()) - 1; i >= 0; i-- {
+		if update[i].next[i].Load() == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i].Store(x.next[i].Load())
+		} else {
+			update[i].span[i]--
+		}
+	}
+	level := s.level.Load()
+	for level > 1 && s.head.next[level-1].Load() == nil {
+		level--
+	}
+	s.level.Store(level)
+	s.size.Add(-1)
+	return true
+}
+
+// Rank 返回键在有序序列中的位置（从0开始），键不存在时返回false
+func (s *SkipList[K, V]) Rank(key K) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update, rank := s.findPath(key)
+	if n := update[0].next[0].Load(); n != nil && n.key == key {
+		return rank[0], true
+	}
+	return 0, false
+}
+
+// Select 返回有序序列中第index个（从0开始）键值对
+func (s *SkipList[K, V]) Select(index int) (Pair[K, V], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if index < 0 || index >= s.Size() {
+		return Pair[K, V]{}, false
+	}
+	target := index + 1
+	x := s.head
+	r := 0
+	for i := int(s.level.Load()) - 1; i >= 0; i-- {
+		for x.next[i].Load() != nil && r+x.span[i] <= target {
+			r += x.span[i]
+			x = x.next[i].Load()
+		}
+		if r == target {
+			break
+		}
+	}
+	return Pair[K, V]{Key: x.key, Value: *x.value.Load()}, true
+}
+
+// All 按键的顺序遍历所有键值对，不加锁
+// 遍历期间发生的写操作可能被看到也可能不被看到，但每个键最多出现一次且顺序不变
+func (s *SkipList[K, V]) All() iter.Seq[Pair[K, V]] {
+	return func(yield func(Pair[K, V]) bool) {
+		for n := s.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+			if !yield(Pair[K, V]{Key: n.key, Value: *n.value.Load()}) {
+				return
+			}
+		}
+	}
+}
+
+// Range 按键的顺序遍历[from, to)范围内的键值对，不加锁
+func (s *SkipList[K, V]) Range(from, to K) iter.Seq[Pair[K, V]] {
+	return func(yield func(Pair[K, V]) bool) {
+		for n := s.findGE(from); n != nil && n.key < to; n = n.next[0].Load() {
+			if !yield(Pair[K, V]{Key: n.key, Value: *n.value.Load()}) {
+				return
+			}
+		}
+	}
+}
+
+// 27.1 并发跳表演示
+func demonstrateSkipList() {
+	fmt.Println("\n=== 并发跳表演示 ===")
+
+	scores := NewSkipList[int, string]()
+	for _, p := range []Pair[int, string]{{88, "张三"}, {95, "李四"}, {72, "王五"}, {95, "李四(更新)"}, {60, "赵六"}, {81, "钱七"}} {
+		scores.Put(p.Key, p.Value)
+	}
+	fmt.Print("按分数排序: ")
+	for p := range scores.All() {
+		fmt.Printf("%d:%s ", p.Key, p.Value)
+	}
+	fmt.Print("\n[70, 90)区间: ")
+	for p := range scores.Range(70, 90) {
+		fmt.Printf("%d:%s ", p.Key, p.Value)
+	}
+	fmt.Println()
+
+	rank, _ := scores.Rank(81)
+	median, _ := scores.Select(scores.Size() / 2)
+	fmt.Printf("81分排名(从0开始): %d, 中位数: %d:%s\n", rank, median.Key, median.Value)
+	scores.Delete(60)
+	lowest, _ := scores.Select(0)
+	fmt.Printf("删除60分后最低分: %d:%s, 共%d人\n", lowest.Key, lowest.Value, scores.Size())
+
+	// 并发读写：读者不加锁，写者串行
+	list := NewSkipList[int, int]()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				list.Put(w*1000+i, i)
+				list.Get(rand.Intn(4000))
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Printf("4个goroutine并发写入后元素个数: %d\n", list.Size())
+}
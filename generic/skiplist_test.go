@@ -0,0 +1,229 @@
+package main
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// checkSkipListOrder 检查All()严格递增，并返回所有键
+func checkSkipListOrder(t *testing.T, s *SkipList[int, int]) []int {
+	t.Helper()
+	var keys []int
+	for p := range s.All() {
+		if len(keys) > 0 && p.Key <= keys[len(keys)-1] {
+			t.Errorf("All() yielded %d after %d", p.Key, keys[len(keys)-1])
+			return keys
+		}
+		keys = append(keys, p.Key)
+	}
+	return keys
+}
+
+func TestSkipListRandomOps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewSkipList[int, int]()
+	ref := map[int]int{}
+	for i := 0; i < 30000; i++ {
+		k := r.Intn(2000)
+		switch r.Intn(4) {
+		case 0, 1:
+			s.Put(k, i)
+			ref[k] = i
+		case 2:
+			_, exists := ref[k]
+			if s.Delete(k) != exists {
+				t.Fatalf("Delete(%d) = %v, want %v", k, !exists, exists)
+			}
+			delete(ref, k)
+		case 3:
+			keys := slices.Sorted(maps.Keys(ref))
+			rank, ok := s.Rank(k)
+			if j, found := slices.BinarySearch(keys, k); ok != found || ok && rank != j {
+				t.Fatalf("Rank(%d) = %d, %v, want %d, %v", k, rank, ok, j, found)
+			}
+			if len(keys) > 0 {
+				q := r.Intn(len(keys))
+				if p, ok := s.Select(q); !ok || p.Key != keys[q] || p.Value != ref[keys[q]] {
+					t.Fatalf("Select(%d) = %v, %v, want key %d", q, p, ok, keys[q])
+				}
+			}
+			from, to := r.Intn(2000), r.Intn(2000)
+			var got, want []int
+			for p := range s.Range(from, to) {
+				got = append(got, p.Key)
+			}
+			for _, k := range keys {
+				if k >= from && k < to {
+					want = append(want, k)
+				}
+			}
+			if !slices.Equal(got, want) {
+				t.Fatalf("Range(%d, %d) = %v, want %v", from, to, got, want)
+			}
+		}
+		if s.Size() != len(ref) {
+			t.Fatalf("Size() = %d, want %d", s.Size(), len(ref))
+		}
+	}
+	for k, v := range ref {
+		if got, ok := s.Get(k); !ok || got != v {
+			t.Fatalf("Get(%d) = %d, %v, want %d", k, got, ok, v)
+		}
+	}
+	if _, ok := s.Select(len(ref)); ok {
+		t.Error("Select(Size()) succeeded")
+	}
+}
+
+func TestSkipListConcurrent(t *testing.T) {
+	// 每个写者负责一部分键，结束后可以与各自的参照结果合并比对
+	// 值总是键的10倍，读者据此检查没有读到错位或未初始化的节点，配合go test -race运行
+	const writers, readers, keys = 4, 4, 512
+	s := NewSkipList[int, int]()
+	refs := make([]map[int]bool, writers)
+	var stop atomic.Bool
+	var wg, readWG sync.WaitGroup
+
+	for w := range writers {
+		refs[w] = map[int]bool{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for range 20000 {
+				k := r.Intn(keys/writers)*writers + w
+				if r.Intn(3) < 2 {
+					s.Put(k, k*10)
+					refs[w][k] = true
+				} else {
+					s.Delete(k)
+					delete(refs[w], k)
+				}
+			}
+		}()
+	}
+	for g := range readers {
+		readWG.Add(1)
+		go func() {
+			defer readWG.Done()
+			r := rand.New(rand.NewSource(int64(100 + g)))
+			for i := 0; !stop.Load(); i++ {
+				k := r.Intn(keys)
+				if v, ok := s.Get(k); ok && v != k*10 {
+					t.Errorf("Get(%d) = %d", k, v)
+					return
+				}
+				switch i % 64 {
+				case 0:
+					checkSkipListOrder(t, s)
+				case 1:
+					prev := -1
+					for p := range s.Range(k, k+100) {
+						if p.Key <= prev || p.Key < k || p.Key >= k+100 || p.Value != p.Key*10 {
+							t.Errorf("Range(%d, %d) yielded %v after %d", k, k+100, p, prev)
+							return
+						}
+						prev = p.Key
+					}
+				case 2:
+					if p, ok := s.Select(r.Intn(keys)); ok && p.Value != p.Key*10 {
+						t.Errorf("Select returned %v", p)
+						return
+					}
+					s.Rank(k)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	stop.Store(true)
+	readWG.Wait()
+
+	var want []int
+	for _, ref := range refs {
+		for k := range ref {
+			want = append(want, k)
+		}
+	}
+	slices.Sort(want)
+	if got := checkSkipListOrder(t, s); !slices.Equal(got, want) {
+		t.Fatalf("final keys differ: got %d keys, want %d", len(got), len(want))
+	}
+	if s.Size() != len(want) {
+		t.Errorf("Size() = %d, want %d", s.Size(), len(want))
+	}
+	for i, k := range want {
+		if rank, ok := s.Rank(k); !ok || rank != i {
+			t.Fatalf("Rank(%d) = %d, %v, want %d", k, rank, ok, i)
+		}
+	}
+}
+
+// lockedMap 用读写锁保护的map，作为跳表并发读基准的对照
+type lockedMap[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// Get 加读锁查找
+func (m *lockedMap[K, V]) Get(key K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[key]
+	return v, ok
+}
+
+// Put 加写锁写入
+func (m *lockedMap[K, V]) Put(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m[key] = value
+}
+
+const benchmarkKeys = 100_000
+
+// benchmarkConcurrentReads 一个写者持续写入的同时，用b.RunParallel并发读取
+func benchmarkConcurrentReads(b *testing.B, get func(int) (int, bool), put func(int, int)) {
+	for i := range benchmarkKeys {
+		put(i, i)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r := rand.New(rand.NewSource(1))
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				put(r.Intn(benchmarkKeys), i)
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			get(r.Intn(benchmarkKeys))
+		}
+	})
+	b.StopTimer()
+	close(stop)
+	<-done
+}
+
+func BenchmarkSkipListGet(b *testing.B) {
+	s := NewSkipList[int, int]()
+	benchmarkConcurrentReads(b, s.Get, s.Put)
+}
+
+func BenchmarkRWMutexMapGet(b *testing.B) {
+	m := &lockedMap[int, int]{m: make(map[int]int, benchmarkKeys)}
+	benchmarkConcurrentReads(b, m.Get, m.Put)
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math/bits"
+	"slices"
+	"unsafe"
+)
+
+// 23. 排序算法
+// 每种算法都提供Ordered版本和less函数版本（Func后缀）
+// 稳定性：MergeSort、RadixSort、SortStableBy是稳定的；IntroSort、HeapSort不稳定
+
+// insertionSortThreshold 小于该长度的区间使用插入排序
+const insertionSortThreshold = 12
+
+// MergeSort 归并排序，稳定，时间O(n log n)，额外空间O(n)
+func MergeSort[T Ordered](s []T) {
+	MergeSortFunc(s, cmp.Less[T])
+}
+
+// MergeSortFunc 使用less函数的归并排序
+func MergeSortFunc[T any](s []T, less func(a, b T) bool) {
+	if len(s) < 2 {
+		return
+	}
+	buf := make([]T, len(s))
+	mergeSort(s, buf, less)
+}
+
+// mergeSort 自顶向下归并，buf与s等长，用作合并时的临时空间
+func mergeSort[T any](s, buf []T, less func(a, b T) bool) {
+	if len(s) <= insertionSortThreshold {
+		insertionSort(s, less)
+		return
+	}
+	mid := len(s) / 2
+	mergeSort(s[:mid], buf[:mid], less)
+	mergeSort(s[mid:], buf[mid:], less)
+	// 两半已经有序时无需合并
+	if !less(s[mid], s[mid-1]) {
+		return
+	}
+	copy(buf, s)
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(s) {
+		// 相等时优先取左半部分，保证稳定
+		if less(buf[j], buf[i]) {
+			s[k] = buf[j]
+			j++
+		} else {
+			s[k] = buf[i]
+			i++
+		}
+		k++
+	}
+	k += copy(s[k:], buf[i:mid])
+	copy(s[k:], buf[j:])
+}
+
+// insertionSort 插入排序，稳定，适合很短的区间
+func insertionSort[T any](s []T, less func(a, b T) bool) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && less(s[j], s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
+
+// HeapSort 堆排序，不稳定，时间O(n log n)，原地排序
+func HeapSort[T Ordered](s []T) {
+	HeapSortFunc(s, cmp.Less[T])
+}
+
+// HeapSortFunc 使用less函数的堆排序
+func HeapSortFunc[T any](s []T, less func(a, b T) bool) {
+	n := len(s)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(s, i, n, less)
+	}
+	for end := n - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		siftDown(s, 0, end, less)
+	}
+}
+
+// siftDown 在大顶堆s[:n]中下沉第i个元素
+func siftDown[T any](s []T, i, n int, less func(a, b T) bool) {
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && less(s[child], s[child+1]) {
+			child++
+		}
+		if !less(s[i], s[child]) {
+			return
+		}
+		s[i], s[child] = s[child], s[i]
+		i = child
+	}
+}
+
+// IntroSort 内省排序，不稳定，时间O(n log n)
+// 以快速排序为主，递归过深时改用堆排序避免退化到O(n²)，短区间使用插入排序
+func IntroSort[T Ordered](s []T) {
+	IntroSortFunc(s, cmp.Less[T])
+}
+
+// IntroSortFunc 使用less函数的内省排序
+func IntroSortFunc[T any](s []T, less func(a, b T) bool) {
+	if len(s) < 2 {
+		return
+	}
+	introSort(s, 2*bits.Len(uint(len(s))), less)
+}
+
+// introSort 对s排序，depth为剩余允许的递归深度
+func introSort[T any](s []T, depth int, less func(a, b T) bool) {
+	for len(s) > insertionSortThreshold {
+		if depth == 0 {
+			HeapSortFunc(s, less)
+			return
+		}
+		depth--
+		lt, gt := partition(s, less)
+		// 先递归较短的一侧，较长的一侧继续循环，栈深度为O(log n)
+		if lt < len(s)-gt {
+			introSort(s[:lt], depth, less)
+			s = s[gt:]
+		} else {
+			introSort(s[gt:], depth, less)
+			s = s[:lt]
+		}
+	}
+	insertionSort(s, less)
+}
+
+// partition 选择主元并进行Bentley-McIlroy三路分区
+// 返回后 s[:lt] 小于主元，s[lt:gt] 等于主元，s[gt:] 大于主元，大量重复值时不会退化
+// 左右两端相向扫描，只交换放错位置的元素，已排序或逆序的输入分区后两侧仍然有序，三数取中继续有效
+func partition[T any](s []T, less func(a, b T) bool) (lt, gt int) {
+	n := len(s)
+	p := choosePivot(s, less)
+	s[0], s[p] = s[p], s[0]
+	pivot := s[0]
+
+	// 扫描过程中与主元相等的元素暂存在两端：s[:a] 和 s[d+1:]
+	a, b, c, d := 1, 1, n-1, n-1
+	for {
+		for b <= c && !less(pivot, s[b]) {
+			if !less(s[b], pivot) {
+				s[a], s[b] = s[b], s[a]
+				a++
+			}
+			b++
+		}
+		for b <= c && !less(s[c], pivot) {
+			if !less(pivot, s[c]) {
+				s[c], s[d] = s[d], s[c]
+				d--
+			}
+			c--
+		}
+		if b > c {
+			break
+		}
+		s[b], s[c] = s[c], s[b]
+		b++
+		c--
+	}
+
+	// 把两端相等的元素交换到中间
+	r := min(a, b-a)
+	swapRange(s[:r], s[b-r:b])
+	r = min(d-c, n-1-d)
+	swapRange(s[b:b+r], s[n-r:])
+	return b - a, n - (d - c)
+}
+
+// choosePivot 返回主元的下标，较长的区间使用Tukey九数取中，能抵抗更多的特殊输入模式
+func choosePivot[T any](s []T, less func(a, b T) bool) int {
+	n := len(s)
+	lo, mid, hi := 0, n/2, n-1
+	if n > 40 {
+		step := n / 8
+		lo = medianOfThree(s, lo, lo+step, lo+2*step, less)
+		mid = medianOfThree(s, mid-step, mid, mid+step, less)
+		hi = medianOfThree(s, hi-2*step, hi-step, hi, less)
+	}
+	return medianOfThree(s, lo, mid, hi, less)
+}
+
+// medianOfThree 返回s[i]、s[j]、s[k]中位于中间的那个值的下标，不移动元素
+func medianOfThree[T any](s []T, i, j, k int, less func(a, b T) bool) int {
+	if less(s[j], s[i]) {
+		i, j = j, i
+	}
+	if less(s[k], s[j]) {
+		j = k
+		if less(s[j], s[i]) {
+			j = i
+		}
+	}
+	return j
+}
+
+// swapRange 逐个交换两个等长区间的元素
+func swapRange[T any](x, y []T) {
+	for i := range x {
+		x[i], y[i] = y[i], x[i]
+	}
+}
+
+// RadixSort LSD基数排序，稳定，时间O(w·n)，w为类型的字节数
+// 有符号整数通过翻转符号位转换为无符号顺序
+func RadixSort[T Integer](s []T) {
+	if len(s) < 2 {
+		return
+	}
+	var zero T
+	width := int(unsafe.Sizeof(zero)) * 8
+	signed := ^zero < 0
+
+	key := func(v T) uint64 {
+		k := uint64(v)
+		if signed {
+			k ^= 1 << (width - 1)
+		}
+		return k
+	}
+
+	buf := make([]T, len(s))
+	src, dst := s, buf
+	for shift := 0; shift < width; shift += 8 {
+		var counts [256]int
+		for _, v := range src {
+			counts[(key(v)>>shift)&0xff]++
+		}
+		// 这一字节所有元素都相同时跳过
+		if slices.Contains(counts[:], len(src)) {
+			continue
+		}
+		offset := 0
+		for i, c := range counts {
+			counts[i] = offset
+			offset += c
+		}
+		for _, v := range src {
+			b := (key(v) >> shift) & 0xff
+			dst[counts[b]] = v
+			counts[b]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// SortStableBy 按key函数的结果稳定排序，每个元素的key只计算一次
+func SortStableBy[T any, K Ordered](s []T, key func(T) K) {
+	keyed := make([]Pair[K, T], len(s))
+	for i, v := range s {
+		keyed[i] = Pair[K, T]{Key: key(v), Value: v}
+	}
+	MergeSortFunc(keyed, func(a, b Pair[K, T]) bool {
+		return cmp.Less(a.Key, b.Key)
+	})
+	for i, p := range keyed {
+		s[i] = p.Value
+	}
+}
+
+// sortAlgorithms 演示中对比的排序算法
+var sortAlgorithms = []struct {
+	name string
+	sort func([]int)
+}{
+	{"slices.Sort", slices.Sort[[]int]},
+	{"MergeSort", MergeSort[int]},
+	{"IntroSort", IntroSort[int]},
+	{"HeapSort", HeapSort[int]},
+	{"RadixSort", RadixSort[int]},
+}
+
+// 23.1 排序算法演示
+func demonstrateSorting() {
+	fmt.Println("\n=== 排序算法演示 ===")
+
+	data := []int{38, -27, 43, 3, 9, -82, 10, 3}
+	for _, alg := range sortAlgorithms {
+		s := slices.Clone(data)
+		alg.sort(s)
+		fmt.Printf("%-12s %v\n", alg.name+":", s)
+	}
+
+	words := []string{"banana", "Apple", "cherry", "apple"}
+	MergeSortFunc(words, func(a, b string) bool { return len(a) < len(b) })
+	fmt.Printf("按长度稳定排序: %v\n", words)
+
+	type Employee struct {
+		Name string
+		Dept string
+	}
+	employees := []Employee{
+		{"张三", "研发"}, {"李四", "市场"}, {"王五", "研发"}, {"赵六", "财务"}, {"钱七", "市场"},
+	}
+	SortStableBy(employees, func(e Employee) string { return e.Dept })
+	fmt.Printf("按部门稳定排序(同部门保持原顺序): %v\n", employees)
+}
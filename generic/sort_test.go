@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// sortDistributions 测试和基准使用的输入分布
+var sortDistributions = []struct {
+	name     string
+	generate func(r *rand.Rand, n int) []int
+}{
+	{"随机", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = r.Int() - r.Int()
+		}
+		return s
+	}},
+	{"已排序", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		return s
+	}},
+	{"逆序", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = n - i
+		}
+		return s
+	}},
+	{"大量重复值", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = r.Intn(8)
+		}
+		return s
+	}},
+	{"锯齿", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i % 100
+		}
+		return s
+	}},
+	{"几乎有序", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		for range n / 100 {
+			i, j := r.Intn(n), r.Intn(n)
+			s[i], s[j] = s[j], s[i]
+		}
+		return s
+	}},
+}
+
+func TestSortAlgorithms(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, dist := range sortDistributions {
+		for _, n := range []int{0, 1, 2, 11, 12, 13, 41, 100, 1000, 10_000} {
+			input := dist.generate(r, n)
+			want := slices.Clone(input)
+			slices.Sort(want)
+			for _, alg := range sortAlgorithms {
+				got := slices.Clone(input)
+				alg.sort(got)
+				if !slices.Equal(got, want) {
+					t.Fatalf("%s on %s n=%d: result differs from slices.Sort", alg.name, dist.name, n)
+				}
+			}
+		}
+	}
+}
+
+func TestRadixSortWidths(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	i8 := make([]int8, 1000)
+	u16 := make([]uint16, 1000)
+	i64 := make([]int64, 1000)
+	for i := range i8 {
+		i8[i] = int8(r.Intn(256) - 128)
+		u16[i] = uint16(r.Intn(1 << 16))
+		i64[i] = r.Int63() - r.Int63()
+	}
+	i64[0], i64[1] = -1<<63, 1<<63-1
+
+	checkRadix(t, i8)
+	checkRadix(t, u16)
+	checkRadix(t, i64)
+}
+
+// checkRadix 检查RadixSort与slices.Sort的结果一致
+func checkRadix[T Integer](t *testing.T, input []T) {
+	t.Helper()
+	got, want := slices.Clone(input), slices.Clone(input)
+	RadixSort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("RadixSort[%T] result differs from slices.Sort", input[0])
+	}
+}
+
+func TestStableSorts(t *testing.T) {
+	type item struct{ key, seq int }
+	r := rand.New(rand.NewSource(1))
+	input := make([]item, 5000)
+	for i := range input {
+		input[i] = item{r.Intn(50), i}
+	}
+	want := slices.Clone(input)
+	slices.SortStableFunc(want, func(a, b item) int { return a.key - b.key })
+
+	merged := slices.Clone(input)
+	MergeSortFunc(merged, func(a, b item) bool { return a.key < b.key })
+	if !slices.Equal(merged, want) {
+		t.Error("MergeSortFunc is not stable")
+	}
+	byKey := slices.Clone(input)
+	SortStableBy(byKey, func(it item) int { return it.key })
+	if !slices.Equal(byKey, want) {
+		t.Error("SortStableBy is not stable")
+	}
+
+	// 每个元素的key只计算一次
+	calls := 0
+	SortStableBy(slices.Clone(input), func(it item) int { calls++; return it.key })
+	if calls != len(input) {
+		t.Errorf("key called %d times, want %d", calls, len(input))
+	}
+}
+
+func TestIntroSortAvoidsHeapFallback(t *testing.T) {
+	// 有序、逆序等常见输入应当始终走快速排序，比较次数保持在n log n量级
+	r := rand.New(rand.NewSource(1))
+	const n = 100_000
+	for _, dist := range sortDistributions {
+		s := dist.generate(r, n)
+		comparisons := 0
+		IntroSortFunc(s, func(a, b int) bool { comparisons++; return a < b })
+		if limit := 3 * n * bits.Len(n); comparisons > limit {
+			t.Errorf("%s: %d comparisons, want at most %d", dist.name, comparisons, limit)
+		}
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	for _, n := range []int{1_000, 100_000} {
+		for _, dist := range sortDistributions {
+			input := dist.generate(rand.New(rand.NewSource(1)), n)
+			for _, alg := range sortAlgorithms {
+				b.Run(fmt.Sprintf("%s/%s/n=%d", alg.name, dist.name, n), func(b *testing.B) {
+					work := make([]int, n)
+					for b.Loop() {
+						copy(work, input)
+						alg.sort(work)
+					}
+				})
+			}
+		}
+	}
+}
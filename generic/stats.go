@@ -0,0 +1,353 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// 21. 统计函数
+// 基于Integer和Float约束的统计工具，结果统一以float64返回，避免整数除法截断
+// time.Duration 的底层类型是int64，因此可以直接用于延迟统计
+
+// Real 实数类型约束：整数或浮点数
+type Real interface {
+	Integer | Float
+}
+
+var (
+	// ErrEmptySample 样本为空
+	ErrEmptySample = errors.New("样本为空")
+	// ErrInsufficientData 样本数量不足，例如样本方差至少需要两个值
+	ErrInsufficientData = errors.New("样本数量不足")
+	// ErrPercentileRange 百分位不在[0, 100]范围内
+	ErrPercentileRange = errors.New("百分位必须在0到100之间")
+)
+
+// Mean 算术平均值
+func Mean[T Real](values []T) (float64, error) {
+	if len(values) == 0 {
+		return 0, ErrEmptySample
+	}
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	return sum / float64(len(values)), nil
+}
+
+// Median 中位数，偶数个值时取中间两个值的平均
+func Median[T Real](values []T) (float64, error) {
+	return Percentile(values, 50)
+}
+
+// Mode 众数，出现次数相同时按升序返回所有众数
+func Mode[T Real](values []T) ([]T, error) {
+	if len(values) == 0 {
+		return nil, ErrEmptySample
+	}
+	counts := make(map[T]int)
+	best := 0
+	for _, v := range values {
+		counts[v]++
+		best = max(best, counts[v])
+	}
+	var modes []T
+	for v, c := range counts {
+		if c == best {
+			modes = append(modes, v)
+		}
+	}
+	slices.Sort(modes)
+	return modes, nil
+}
+
+// Variance 总体方差
+func Variance[T Real](values []T) (float64, error) {
+	if len(values) == 0 {
+		return 0, ErrEmptySample
+	}
+	return sumSquaredDeviations(values) / float64(len(values)), nil
+}
+
+// SampleVariance 样本方差（除以n-1）
+func SampleVariance[T Real](values []T) (float64, error) {
+	if len(values) < 2 {
+		return 0, ErrInsufficientData
+	}
+	return sumSquaredDeviations(values) / float64(len(values)-1), nil
+}
+
+// StdDev 总体标准差
+func StdDev[T Real](values []T) (float64, error) {
+	v, err := Variance(values)
+	return math.Sqrt(v), err
+}
+
+// SampleStdDev 样本标准差
+func SampleStdDev[T Real](values []T) (float64, error) {
+	v, err := SampleVariance(values)
+	return math.Sqrt(v), err
+}
+
+// sumSquaredDeviations 计算离差平方和，先求均值再累加，比直接用平方和公式更稳定
+func sumSquaredDeviations[T Real](values []T) float64 {
+	mean, _ := Mean(values)
+	var sum float64
+	for _, v := range values {
+		d := float64(v) - mean
+		sum += d * d
+	}
+	return sum
+}
+
+// Percentile 第p百分位数（0 <= p <= 100），在相邻两个值之间线性插值
+// 与Excel的PERCENTILE.INC以及NumPy默认的linear方法一致
+func Percentile[T Real](values []T, p float64) (float64, error) {
+	if len(values) == 0 {
+		return 0, ErrEmptySample
+	}
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, ErrPercentileRange
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return percentileSorted(sorted, p), nil
+}
+
+// Percentiles 一次计算多个百分位，只排序一次
+func Percentiles[T Real](values []T, ps ...float64) ([]float64, error) {
+	if len(values) == 0 {
+		return nil, ErrEmptySample
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	result := make([]float64, len(ps))
+	for i, p := range ps {
+		if p < 0 || p > 100 || math.IsNaN(p) {
+			return nil, ErrPercentileRange
+		}
+		result[i] = percentileSorted(sorted, p)
+	}
+	return result, nil
+}
+
+// percentileSorted 在已排序的切片上计算百分位
+func percentileSorted[T Real](sorted []T, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return float64(sorted[lower])
+	}
+	frac := rank - float64(lower)
+	return float64(sorted[lower]) + frac*(float64(sorted[upper])-float64(sorted[lower]))
+}
+
+// MinOf 返回最小值
+func MinOf[T Real](values []T) (T, error) {
+	if len(values) == 0 {
+		var zero T
+		return zero, ErrEmptySample
+	}
+	return slices.Min(values), nil
+}
+
+// MaxOf 返回最大值
+func MaxOf[T Real](values []T) (T, error) {
+	if len(values) == 0 {
+		var zero T
+		return zero, ErrEmptySample
+	}
+	return slices.Max(values), nil
+}
+
+// HistogramBucket 直方图的一个桶，统计 (Lower, Upper] 区间内的值
+// 第一个桶没有下界，最后一个桶没有上界，分别用Unbounded标记
+type HistogramBucket[T Real] struct {
+	Lower          T
+	Upper          T
+	LowerUnbounded bool
+	UpperUnbounded bool
+	Count          int
+}
+
+// String 以区间形式输出桶
+func (b HistogramBucket[T]) String() string {
+	lower, upper := fmt.Sprint(b.Lower), fmt.Sprint(b.Upper)
+	if b.LowerUnbounded {
+		lower = "-∞"
+	}
+	if b.UpperUnbounded {
+		upper = "+∞"
+	}
+	return fmt.Sprintf("(%s, %s]: %d", lower, upper, b.Count)
+}
+
+// Histogram 按升序的边界对值进行分桶，n个边界产生n+1个桶
+func Histogram[T Real](values []T, bounds []T) []HistogramBucket[T] {
+	buckets := make([]HistogramBucket[T], len(bounds)+1)
+	for i := range buckets {
+		if i == 0 {
+			buckets[i].LowerUnbounded = true
+		} else {
+			buckets[i].Lower = bounds[i-1]
+		}
+		if i == len(bounds) {
+			buckets[i].UpperUnbounded = true
+		} else {
+			buckets[i].Upper = bounds[i]
+		}
+	}
+	for _, v := range values {
+		// 第一个大于等于v的边界所在的桶
+		i, _ := slices.BinarySearch(bounds, v)
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// LinearBounds 生成从start开始、间隔为width的count个边界
+func LinearBounds[T Real](start, width T, count int) []T {
+	bounds := make([]T, count)
+	for i := range bounds {
+		bounds[i] = start + T(i)*width
+	}
+	return bounds
+}
+
+// RunningStats 流式统计累加器，使用Welford算法在线更新均值和方差
+// 不需要保存所有样本，适合持续统计请求延迟等数据
+type RunningStats[T Real] struct {
+	count int
+	mean  float64
+	m2    float64 // 离差平方和
+	min   T
+	max   T
+}
+
+// Add 加入一个样本
+func (s *RunningStats[T]) Add(value T) {
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+	s.count++
+	x := float64(value)
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (x - s.mean)
+}
+
+// Merge 合并另一个累加器，可用于汇总多个goroutine各自的统计结果
+func (s *RunningStats[T]) Merge(other *RunningStats[T]) {
+	if other.count == 0 {
+		return
+	}
+	if s.count == 0 {
+		*s = *other
+		return
+	}
+	n := float64(s.count + other.count)
+	delta := other.mean - s.mean
+	s.m2 += other.m2 + delta*delta*float64(s.count)*float64(other.count)/n
+	s.mean += delta * float64(other.count) / n
+	s.count += other.count
+	s.min = min(s.min, other.min)
+	s.max = max(s.max, other.max)
+}
+
+// Count 样本数量
+func (s *RunningStats[T]) Count() int {
+	return s.count
+}
+
+// Mean 均值
+func (s *RunningStats[T]) Mean() float64 {
+	return s.mean
+}
+
+// Variance 总体方差
+func (s *RunningStats[T]) Variance() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.m2 / float64(s.count)
+}
+
+// SampleVariance 样本方差
+func (s *RunningStats[T]) SampleVariance() float64 {
+	if s.count < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.count-1)
+}
+
+// StdDev 总体标准差
+func (s *RunningStats[T]) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// SampleStdDev 样本标准差
+func (s *RunningStats[T]) SampleStdDev() float64 {
+	return math.Sqrt(s.SampleVariance())
+}
+
+// Min 最小值
+func (s *RunningStats[T]) Min() T {
+	return s.min
+}
+
+// Max 最大值
+func (s *RunningStats[T]) Max() T {
+	return s.max
+}
+
+// 21.1 统计函数演示
+func demonstrateStatistics() {
+	fmt.Println("\n=== 统计函数演示 ===")
+
+	scores := []int{85, 92, 78, 92, 66, 85, 92, 70}
+	mean, _ := Mean(scores)
+	median, _ := Median(scores)
+	mode, _ := Mode(scores)
+	stddev, _ := StdDev(scores)
+	sampleStddev, _ := SampleStdDev(scores)
+	fmt.Printf("成绩: %v\n", scores)
+	fmt.Printf("均值: %.2f, 中位数: %.1f, 众数: %v\n", mean, median, mode)
+	fmt.Printf("总体标准差: %.2f, 样本标准差: %.2f\n", stddev, sampleStddev)
+
+	if _, err := Mean([]float64{}); err != nil {
+		fmt.Printf("空样本: %v\n", err)
+	}
+
+	// 延迟统计：time.Duration满足Integer约束
+	latencies := []time.Duration{
+		12 * time.Millisecond, 8 * time.Millisecond, 15 * time.Millisecond,
+		230 * time.Millisecond, 9 * time.Millisecond, 11 * time.Millisecond,
+		14 * time.Millisecond, 95 * time.Millisecond, 10 * time.Millisecond,
+		13 * time.Millisecond,
+	}
+	ps, _ := Percentiles(latencies, 50, 90, 99)
+	for i, p := range []int{50, 90, 99} {
+		fmt.Printf("延迟 p%d: %v\n", p, time.Duration(ps[i]).Round(time.Microsecond))
+	}
+
+	fmt.Println("延迟直方图:")
+	for _, b := range Histogram(latencies, LinearBounds(10*time.Millisecond, 40*time.Millisecond, 3)) {
+		fmt.Printf("  %v\n", b)
+	}
+
+	// 流式统计：模拟在中间件中逐个记录请求耗时
+	var running RunningStats[time.Duration]
+	for _, l := range latencies {
+		running.Add(l)
+	}
+	fmt.Printf("流式统计: 次数=%d, 均值=%v, 标准差=%v, 最小=%v, 最大=%v\n",
+		running.Count(), time.Duration(running.Mean()), time.Duration(running.StdDev()).Round(time.Microsecond),
+		running.Min(), running.Max())
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// approxEqual 判断两个浮点数在相对误差内相等
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(1, math.Abs(a), math.Abs(b))
+}
+
+func TestDescriptiveStats(t *testing.T) {
+	values := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	checks := []struct {
+		name string
+		fn   func([]int) (float64, error)
+		want float64
+	}{
+		{"Mean", Mean[int], 5},
+		{"Median", Median[int], 4.5},
+		{"Variance", Variance[int], 4},
+		{"StdDev", StdDev[int], 2},
+		{"SampleVariance", SampleVariance[int], 32.0 / 7},
+	}
+	for _, c := range checks {
+		if got, err := c.fn(values); err != nil || !approxEqual(got, c.want) {
+			t.Errorf("%s = %v, %v, want %v", c.name, got, err, c.want)
+		}
+	}
+	if m, _ := Mode([]int{3, 1, 3, 1, 2}); !slices.Equal(m, []int{1, 3}) {
+		t.Errorf("Mode = %v, want [1 3]", m)
+	}
+	if lo, _ := MinOf(values); lo != 2 {
+		t.Errorf("MinOf = %d", lo)
+	}
+	if hi, _ := MaxOf(values); hi != 9 {
+		t.Errorf("MaxOf = %d", hi)
+	}
+}
+
+func TestStatsErrors(t *testing.T) {
+	var empty []float64
+	if _, err := Mean(empty); !errors.Is(err, ErrEmptySample) {
+		t.Errorf("Mean(empty) = %v", err)
+	}
+	if _, err := Mode(empty); !errors.Is(err, ErrEmptySample) {
+		t.Errorf("Mode(empty) = %v", err)
+	}
+	if _, err := MinOf(empty); !errors.Is(err, ErrEmptySample) {
+		t.Errorf("MinOf(empty) = %v", err)
+	}
+	if _, err := SampleStdDev([]float64{1}); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("SampleStdDev(one) = %v", err)
+	}
+	for _, p := range []float64{-1, 100.5, math.NaN()} {
+		if _, err := Percentile([]float64{1, 2}, p); !errors.Is(err, ErrPercentileRange) {
+			t.Errorf("Percentile(%v) = %v", p, err)
+		}
+		if _, err := Percentiles([]float64{1, 2}, 50, p); !errors.Is(err, ErrPercentileRange) {
+			t.Errorf("Percentiles(50, %v) = %v", p, err)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	// 与NumPy默认的linear方法对照
+	values := []float64{15, 20, 35, 40, 50}
+	tests := []struct{ p, want float64 }{
+		{0, 15}, {25, 20}, {40, 29}, {50, 35}, {90, 46}, {100, 50},
+	}
+	ps := make([]float64, len(tests))
+	for i, tt := range tests {
+		ps[i] = tt.p
+		if got, err := Percentile(values, tt.p); err != nil || !approxEqual(got, tt.want) {
+			t.Errorf("Percentile(%v) = %v, %v, want %v", tt.p, got, err, tt.want)
+		}
+	}
+	all, err := Percentiles(values, ps...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range tests {
+		if !approxEqual(all[i], tt.want) {
+			t.Errorf("Percentiles[%v] = %v, want %v", tt.p, all[i], tt.want)
+		}
+	}
+	if single, _ := Percentile([]int{7}, 33); single != 7 {
+		t.Errorf("Percentile of one value = %v", single)
+	}
+	// 不修改输入
+	if !slices.Equal(values, []float64{15, 20, 35, 40, 50}) {
+		t.Error("Percentile modified its input")
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	bounds := LinearBounds(10, 10, 3)
+	if !slices.Equal(bounds, []int{10, 20, 30}) {
+		t.Fatalf("LinearBounds = %v", bounds)
+	}
+	// 区间左开右闭，边界值落在左边的桶
+	buckets := Histogram([]int{-5, 10, 11, 20, 25, 30, 31, 100}, bounds)
+	want := []int{2, 2, 2, 2}
+	for i, b := range buckets {
+		if b.Count != want[i] {
+			t.Errorf("bucket %s count = %d, want %d", b, b.Count, want[i])
+		}
+	}
+	if !buckets[0].LowerUnbounded || !buckets[3].UpperUnbounded || buckets[1].Lower != 10 || buckets[1].Upper != 20 {
+		t.Errorf("bucket bounds wrong: %v", buckets)
+	}
+	if s := buckets[0].String(); s != "(-∞, 10]: 2" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestRunningStatsMatchesBatch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, 1000)
+	for i := range values {
+		values[i] = r.NormFloat64()*50 + 1e6
+	}
+
+	// 分成多段分别累加再合并，结果应与批量计算一致
+	var total RunningStats[float64]
+	for _, chunk := range Chunk(values, 137) {
+		var part RunningStats[float64]
+		for _, v := range chunk {
+			part.Add(v)
+		}
+		total.Merge(&part)
+	}
+	total.Merge(&RunningStats[float64]{})
+
+	mean, _ := Mean(values)
+	variance, _ := Variance(values)
+	sampleVar, _ := SampleVariance(values)
+	if total.Count() != len(values) {
+		t.Errorf("Count() = %d", total.Count())
+	}
+	if !approxEqual(total.Mean(), mean) {
+		t.Errorf("Mean() = %v, want %v", total.Mean(), mean)
+	}
+	if math.Abs(total.Variance()-variance) > 1e-6*variance || math.Abs(total.SampleVariance()-sampleVar) > 1e-6*sampleVar {
+		t.Errorf("Variance() = %v/%v, want %v/%v", total.Variance(), total.SampleVariance(), variance, sampleVar)
+	}
+	if total.Min() != slices.Min(values) || total.Max() != slices.Max(values) {
+		t.Errorf("Min/Max = %v/%v", total.Min(), total.Max())
+	}
+
+	var empty RunningStats[int]
+	if empty.Variance() != 0 || empty.SampleStdDev() != 0 {
+		t.Error("empty RunningStats reported non-zero variance")
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+)
+
+// 13. 有序映射 TreeMap
+// 基于AVL平衡二叉搜索树实现，所有键按升序保存
+// 插入、查找、删除的时间复杂度都是O(log n)
+
+// TreeMap 泛型有序映射
+type TreeMap[K Ordered, V any] struct {
+	root *treeNode[K, V]
+	size int
+}
+
+// treeNode AVL树节点
+type treeNode[K Ordered, V any] struct {
+	key    K
+	value  V
+	height int
+	left   *treeNode[K, V]
+	right  *treeNode[K, V]
+}
+
+// NewTreeMap 创建新的有序映射
+func NewTreeMap[K Ordered, V any]() *TreeMap[K, V] {
+	return &TreeMap[K, V]{}
+}
+
+// Size 返回映射中键值对的数量
+func (m *TreeMap[K, V]) Size() int {
+	return m.size
+}
+
+// IsEmpty 检查映射是否为空
+func (m *TreeMap[K, V]) IsEmpty() bool {
+	return m.size == 0
+}
+
+// Put 插入或更新键值对
+func (m *TreeMap[K, V]) Put(key K, value V) {
+	var inserted bool
+	m.root, inserted = m.root.put(key, value)
+	if inserted {
+		m.size++
+	}
+}
+
+// Get 获取键对应的值
+func (m *TreeMap[K, V]) Get(key K) (V, bool) {
+	current := m.root
+	for current != nil {
+		switch {
+		case key < current.key:
+			current = current.left
+		case key > current.key:
+			current = current.right
+		default:
+			return current.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Contains 检查键是否存在
+func (m *TreeMap[K, V]) Contains(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Delete 删除键，返回键是否存在
+func (m *TreeMap[K, V]) Delete(key K) bool {
+	var deleted bool
+	m.root, deleted = m.root.delete(key)
+	if deleted {
+		m.size--
+	}
+	return deleted
+}
+
+// Min 返回最小的键值对
+func (m *TreeMap[K, V]) Min() (Pair[K, V], bool) {
+	if m.root == nil {
+		return Pair[K, V]{}, false
+	}
+	return m.root.min().pair(), true
+}
+
+// Max 返回最大的键值对
+func (m *TreeMap[K, V]) Max() (Pair[K, V], bool) {
+	if m.root == nil {
+		return Pair[K, V]{}, false
+	}
+	node := m.root
+	for node.right != nil {
+		node = node.right
+	}
+	return node.pair(), true
+}
+
+// Floor 返回小于等于key的最大键值对
+func (m *TreeMap[K, V]) Floor(key K) (Pair[K, V], bool) {
+	var found *treeNode[K, V]
+	current := m.root
+	for current != nil {
+		switch {
+		case key < current.key:
+			current = current.left
+		case key > current.key:
+			found = current
+			current = current.right
+		default:
+			return current.pair(), true
+		}
+	}
+	if found == nil {
+		return Pair[K, V]{}, false
+	}
+	return found.pair(), true
+}
+
+// Ceiling 返回大于等于key的最小键值对
+func (m *TreeMap[K, V]) Ceiling(key K) (Pair[K, V], bool) {
+	var found *treeNode[K, V]
+	current := m.root
+	for current != nil {
+		switch {
+		case key < current.key:
+			found = current
+			current = current.left
+		case key > current.key:
+			current = current.right
+		default:
+			return current.pair(), true
+		}
+	}
+	if found == nil {
+		return Pair[K, V]{}, false
+	}
+	return found.pair(), true
+}
+
+// All 按键的升序遍历所有键值对
+func (m *TreeMap[K, V]) All() iter.Seq[Pair[K, V]] {
+	return func(yield func(Pair[K, V]) bool) {
+		m.root.walk(nil, nil, yield)
+	}
+}
+
+// Range 按键的升序遍历 [from, to) 区间内的键值对
+func (m *TreeMap[K, V]) Range(from, to K) iter.Seq[Pair[K, V]] {
+	return func(yield func(Pair[K, V]) bool) {
+		m.root.walk(&from, &to, yield)
+	}
+}
+
+// Keys 返回按升序排列的所有键
+func (m *TreeMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.size)
+	for p := range m.All() {
+		keys = append(keys, p.Key)
+	}
+	return keys
+}
+
+// pair 将节点转换为键值对
+func (n *treeNode[K, V]) pair() Pair[K, V] {
+	return Pair[K, V]{Key: n.key, Value: n.value}
+}
+
+// walk 中序遍历，from为nil表示无下界，to为nil表示无上界
+// 返回false表示调用方要求停止遍历
+func (n *treeNode[K, V]) walk(from, to *K, yield func(Pair[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+	// 只有当前键大于下界时，左子树中才可能有符合条件的键
+	if from == nil || *from < n.key {
+		if !n.left.walk(from, to, yield) {
+			return false
+		}
+	}
+	if (from == nil || *from <= n.key) && (to == nil || n.key < *to) {
+		if !yield(n.pair()) {
+			return false
+		}
+	}
+	// 只有当前键小于上界时，右子树中才可能有符合条件的键
+	if to == nil || n.key < *to {
+		return n.right.walk(from, to, yield)
+	}
+	return true
+}
+
+// nodeHeight 返回节点高度，空节点高度为0
+func nodeHeight[K Ordered, V any](n *treeNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update 重新计算节点高度
+func (n *treeNode[K, V]) update() {
+	n.height = max(nodeHeight(n.left), nodeHeight(n.right)) + 1
+}
+
+// balanceFactor 返回左右子树的高度差
+func (n *treeNode[K, V]) balanceFactor() int {
+	return nodeHeight(n.left) - nodeHeight(n.right)
+}
+
+// rotateRight 右旋
+func (n *treeNode[K, V]) rotateRight() *treeNode[K, V] {
+	left := n.left
+	n.left = left.right
+	left.right = n
+	n.update()
+	left.update()
+	return left
+}
+
+// rotateLeft 左旋
+func (n *treeNode[K, V]) rotateLeft() *treeNode[K, V] {
+	right := n.right
+	n.right = right.left
+	right.left = n
+	n.update()
+	right.update()
+	return right
+}
+
+// rebalance 更新高度并在失衡时旋转，返回新的子树根节点
+func (n *treeNode[K, V]) rebalance() *treeNode[K, V] {
+	n.update()
+	switch bf := n.balanceFactor(); {
+	case bf > 1:
+		if n.left.balanceFactor() < 0 {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case bf < -1:
+		if n.right.balanceFactor() > 0 {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// put 在子树中插入键值对，返回新的子树根节点以及是否新增了节点
+func (n *treeNode[K, V]) put(key K, value V) (*treeNode[K, V], bool) {
+	if n == nil {
+		return &treeNode[K, V]{key: key, value: value, height: 1}, true
+	}
+
+	var inserted bool
+	switch {
+	case key < n.key:
+		n.left, inserted = n.left.put(key, value)
+	case key > n.key:
+		n.right, inserted = n.right.put(key, value)
+	default:
+		n.value = value
+		return n, false
+	}
+	return n.rebalance(), inserted
+}
+
+// min 返回子树中键最小的节点
+func (n *treeNode[K, V]) min() *treeNode[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+// delete 在子树中删除键，返回新的子树根节点以及是否删除了节点
+func (n *treeNode[K, V]) delete(key K) (*treeNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var deleted bool
+	switch {
+	case key < n.key:
+		n.left, deleted = n.left.delete(key)
+	case key > n.key:
+		n.right, deleted = n.right.delete(key)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// 有两个子节点时，用右子树中的最小节点替换当前节点
+		successor := n.right.min()
+		n.key, n.value = successor.key, successor.value
+		n.right, _ = n.right.delete(successor.key)
+		deleted = true
+	}
+	return n.rebalance(), deleted
+}
+
+// 13.1 有序映射演示
+func demonstrateTreeMap() {
+	fmt.Println("\n=== 有序映射 TreeMap 演示 ===")
+
+	scores := NewTreeMap[string, int]()
+	scores.Put("张三", 85)
+	scores.Put("李四", 92)
+	scores.Put("王五", 78)
+	scores.Put("赵六", 66)
+	scores.Put("Alice", 95)
+	scores.Put("Bob", 70)
+
+	// 更新已存在的键不会改变大小
+	scores.Put("Bob", 73)
+	fmt.Printf("映射大小: %d\n", scores.Size())
+
+	fmt.Println("按键升序遍历:")
+	for p := range scores.All() {
+		fmt.Printf("  %s: %d\n", p.Key, p.Value)
+	}
+
+	if v, ok := scores.Get("李四"); ok {
+		fmt.Printf("Get(\"李四\"): %d\n", v)
+	}
+
+	ages := NewTreeMap[int, string]()
+	for _, age := range []int{18, 25, 30, 42, 55, 60} {
+		ages.Put(age, fmt.Sprintf("年龄%d", age))
+	}
+
+	if p, ok := ages.Floor(40); ok {
+		fmt.Printf("Floor(40): %d -> %s\n", p.Key, p.Value)
+	}
+	if p, ok := ages.Ceiling(40); ok {
+		fmt.Printf("Ceiling(40): %d -> %s\n", p.Key, p.Value)
+	}
+	if p, ok := ages.Min(); ok {
+		fmt.Printf("Min: %d\n", p.Key)
+	}
+	if p, ok := ages.Max(); ok {
+		fmt.Printf("Max: %d\n", p.Key)
+	}
+
+	fmt.Print("Range[25, 55): ")
+	for p := range ages.Range(25, 55) {
+		fmt.Printf("%d ", p.Key)
+	}
+	fmt.Println()
+
+	ages.Delete(30)
+	fmt.Printf("删除30后的键: %v\n", ages.Keys())
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkAVL 校验二叉搜索树的有序性、高度和平衡因子，返回子树高度
+func checkAVL[K Ordered, V any](t *testing.T, n *treeNode[K, V], lo, hi *K) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	if (lo != nil && n.key <= *lo) || (hi != nil && n.key >= *hi) {
+		t.Fatalf("key %v out of order", n.key)
+	}
+	l := checkAVL(t, n.left, lo, &n.key)
+	r := checkAVL(t, n.right, &n.key, hi)
+	if d := l - r; d < -1 || d > 1 {
+		t.Fatalf("node %v unbalanced: left %d right %d", n.key, l, r)
+	}
+	h := max(l, r) + 1
+	if n.height != h {
+		t.Fatalf("node %v height = %d, want %d", n.key, n.height, h)
+	}
+	return h
+}
+
+func TestTreeMapRandomOps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := NewTreeMap[int, int]()
+	ref := map[int]int{}
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(500)
+		switch r.Intn(3) {
+		case 0, 1:
+			m.Put(k, i)
+			ref[k] = i
+		case 2:
+			_, want := ref[k]
+			if got := m.Delete(k); got != want {
+				t.Fatalf("Delete(%d) = %v, want %v", k, got, want)
+			}
+			delete(ref, k)
+		}
+		if m.Size() != len(ref) {
+			t.Fatalf("Size() = %d, want %d", m.Size(), len(ref))
+		}
+		if v, ok := m.Get(k); ok != m.Contains(k) || (ok && v != ref[k]) {
+			t.Fatalf("Get(%d) = %d, %v", k, v, ok)
+		}
+	}
+	checkAVL(t, m.root, nil, nil)
+
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(m.Keys(), keys) {
+		t.Fatalf("Keys() not sorted or incomplete")
+	}
+	if p, ok := m.Min(); !ok || p.Key != keys[0] {
+		t.Fatalf("Min() = %v, %v", p, ok)
+	}
+	if p, ok := m.Max(); !ok || p.Key != keys[len(keys)-1] {
+		t.Fatalf("Max() = %v, %v", p, ok)
+	}
+}
+
+func TestTreeMapFloorCeilingRange(t *testing.T) {
+	m := NewTreeMap[int, string]()
+	for _, k := range []int{10, 20, 30, 40} {
+		m.Put(k, "")
+	}
+	tests := []struct {
+		key                  int
+		floor, ceiling       int
+		hasFloor, hasCeiling bool
+	}{
+		{5, 0, 10, false, true},
+		{10, 10, 10, true, true},
+		{25, 20, 30, true, true},
+		{40, 40, 40, true, true},
+		{45, 40, 0, true, false},
+	}
+	for _, tt := range tests {
+		f, ok := m.Floor(tt.key)
+		if ok != tt.hasFloor || (ok && f.Key != tt.floor) {
+			t.Errorf("Floor(%d) = %v, %v", tt.key, f.Key, ok)
+		}
+		c, ok := m.Ceiling(tt.key)
+		if ok != tt.hasCeiling || (ok && c.Key != tt.ceiling) {
+			t.Errorf("Ceiling(%d) = %v, %v", tt.key, c.Key, ok)
+		}
+	}
+
+	var got []int
+	for p := range m.Range(20, 40) {
+		got = append(got, p.Key)
+	}
+	if !slices.Equal(got, []int{20, 30}) {
+		t.Errorf("Range(20, 40) = %v, want [20 30]", got)
+	}
+	for p := range m.All() {
+		if p.Key == 20 {
+			break
+		}
+	}
+
+	empty := NewTreeMap[int, int]()
+	if _, ok := empty.Min(); ok {
+		t.Error("Min() on empty map reported a value")
+	}
+	if empty.Delete(1) {
+		t.Error("Delete on empty map returned true")
+	}
+}
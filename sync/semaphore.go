@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 15. 加权信号量
+// 用Mutex保护计数，等待者按FIFO顺序排队，每个等待者持有一个通道，获取成功时关闭通道唤醒它
+// 队首的大请求会挡住后面的小请求，避免大请求一直拿不到资源（饥饿）
+
+// ErrWeightTooLarge 请求的数量超过信号量的总容量，永远无法满足
+var ErrWeightTooLarge = errors.New("请求数量超过信号量容量")
+
+// semWaiter 等待获取信号量的goroutine
+type semWaiter struct {
+	n     int64
+	ready chan struct{} // 获取成功时关闭
+}
+
+// Semaphore 支持context的加权信号量
+type Semaphore struct {
+	size    int64
+	mu      sync.Mutex
+	cur     int64
+	waiters []*semWaiter
+}
+
+// NewSemaphore 创建总容量为size的信号量
+func NewSemaphore(size int64) *Semaphore {
+	if size <= 0 {
+		panic("NewSemaphore: 容量必须大于0")
+	}
+	return &Semaphore{size: size}
+}
+
+// Acquire 获取n个资源，资源不足时阻塞，直到获取成功或ctx结束
+// 失败时返回ctx.Err()并且不占用任何资源，n超过总容量时立即返回ErrWeightTooLarge
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if n < 0 {
+		panic("Semaphore: 获取数量不能为负数")
+	}
+	s.mu.Lock()
+	if n > s.size {
+		s.mu.Unlock()
+		return fmt.Errorf("获取%d, 容量%d: %w", n, s.size, ErrWeightTooLarge)
+	}
+	if s.size-s.cur >= n && len(s.waiters) == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	w := &semWaiter{n: n, ready: make(chan struct{})}
+	s.waiters = append(s.waiters, w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-w.ready:
+			// 取消的同时恰好获取成功，归还资源当作没有获取
+			s.cur -= n
+		default:
+			i := slices.Index(s.waiters, w)
+			s.waiters = slices.Delete(s.waiters, i, i+1)
+		}
+		// 离开的可能是挡住其他人的队首
+		s.notifyWaiters()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire 不阻塞地获取n个资源，成功返回true
+func (s *Semaphore) TryAcquire(n int64) bool {
+	if n < 0 {
+		panic("Semaphore: 获取数量不能为负数")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && len(s.waiters) == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release 释放n个资源并唤醒能够满足的等待者，释放的数量超过已获取的数量时panic
+func (s *Semaphore) Release(n int64) {
+	if n < 0 {
+		panic("Semaphore: 释放数量不能为负数")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > s.cur {
+		panic("Semaphore: 释放的数量超过已获取的数量")
+	}
+	s.cur -= n
+	s.notifyWaiters()
+}
+
+// notifyWaiters 按顺序唤醒等待者，队首无法满足时停止，调用者必须持有锁
+func (s *Semaphore) notifyWaiters() {
+	for len(s.waiters) > 0 {
+		w := s.waiters[0]
+		if s.size-s.cur < w.n {
+			break
+		}
+		s.cur += w.n
+		s.waiters[0] = nil
+		s.waiters = s.waiters[1:]
+		close(w.ready)
+	}
+}
+
+// 15.1 加权信号量演示
+func demonstrateSemaphore() {
+	fmt.Println("\n=== 加权信号量演示 ===")
+
+	// 内存预算为100MB，每个任务按需占用
+	mem := NewSemaphore(100)
+	var inUse, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i, need := range []int64{60, 30, 50, 20, 40, 10} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := mem.Acquire(context.Background(), need); err != nil {
+				return
+			}
+			defer mem.Release(need)
+			cur := inUse.Add(need)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Duration(20+10*i) * time.Millisecond)
+			inUse.Add(-need)
+		}()
+	}
+	wg.Wait()
+	fmt.Printf("所有任务完成, 内存占用峰值: %dMB (上限100MB)\n", peak.Load())
+
+	fmt.Printf("TryAcquire(80): %v, 再次TryAcquire(30): %v\n", mem.TryAcquire(80), mem.TryAcquire(30))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	fmt.Printf("等待30MB超时: %v\n", mem.Acquire(ctx, 30))
+	mem.Release(80)
+	fmt.Printf("释放后Acquire(30): %v, 申请超过容量: %v\n",
+		mem.Acquire(context.Background(), 30), mem.Acquire(context.Background(), 200))
+	mem.Release(30)
+}
+
+// 16. Group - 一组协作的goroutine
+// 类似errgroup：Go启动goroutine，第一个错误出现时取消context通知其他goroutine提前结束
+// 与errgroup只返回第一个错误不同，Wait用errors.Join返回所有错误
+
+// Group 一组goroutine，零值可以直接使用（此时没有context，也不限制并发数）
+type Group struct {
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	limit  chan struct{} // 令牌通道，为nil时不限制并发数
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewGroup 创建Group和它的context，任何一个goroutine返回错误或Wait返回时context被取消
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit 限制同时运行的goroutine数量，n<0表示不限制
+// 必须在没有goroutine运行时调用
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.limit = nil
+		return
+	}
+	if len(g.limit) != 0 {
+		panic(fmt.Sprintf("Group: 还有%d个goroutine在运行时不能修改并发限制", len(g.limit)))
+	}
+	g.limit = make(chan struct{}, n)
+}
+
+// Go 在新的goroutine中执行f，达到并发限制时阻塞直到有goroutine结束
+func (g *Group) Go(f func() error) {
+	if g.limit != nil {
+		g.limit <- struct{}{}
+	}
+	g.start(f)
+}
+
+// TryGo 只有在没有达到并发限制时才启动goroutine，返回是否启动
+func (g *Group) TryGo(f func() error) bool {
+	if g.limit != nil {
+		select {
+		case g.limit <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+func (g *Group) start(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := f(); err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+			if g.cancel != nil {
+				// 只有第一次调用会生效，context.Cause返回第一个错误
+				g.cancel(err)
+			}
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.limit != nil {
+		<-g.limit
+	}
+	g.wg.Done()
+}
+
+// Wait 等待所有goroutine结束，返回所有错误合并后的结果，没有错误时返回nil
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(context.Canceled)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+// 16.1 Group演示
+func demonstrateGroup() {
+	fmt.Println("\n=== Group 演示 ===")
+
+	// 并发检查多个服务，最多同时2个，一个失败后其余的提前结束
+	g, ctx := NewGroup(context.Background())
+	g.SetLimit(2)
+	for _, svc := range []struct {
+		name  string
+		delay time.Duration
+		fail  bool
+	}{
+		{"用户服务", 30 * time.Millisecond, false},
+		{"订单服务", 50 * time.Millisecond, true},
+		{"库存服务", 200 * time.Millisecond, false},
+		{"支付服务", 200 * time.Millisecond, false},
+	} {
+		g.Go(func() error {
+			select {
+			case <-time.After(svc.delay):
+				if svc.fail {
+					return fmt.Errorf("%s: 连接被拒绝", svc.name)
+				}
+				fmt.Printf("%s 正常\n", svc.name)
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("%s: 检查被取消: %w", svc.name, ctx.Err())
+			}
+		})
+	}
+	err := g.Wait()
+	fmt.Printf("Wait返回:\n%v\n", err)
+	fmt.Printf("取消原因: %v\n", context.Cause(ctx))
+
+	// 零值Group：不取消其他goroutine，收集所有错误
+	var all Group
+	for i := 1; i <= 4; i++ {
+		all.Go(func() error {
+			if i%2 == 0 {
+				return fmt.Errorf("任务%d失败", i)
+			}
+			return nil
+		})
+	}
+	fmt.Printf("零值Group的所有错误:\n%v\n", all.Wait())
+}
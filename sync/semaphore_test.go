@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waiterCount 返回正在排队的等待者数量
+func waiterCount(s *Semaphore) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.waiters)
+}
+
+// startAcquire 在新的goroutine中获取n个资源，等它进入队列后返回结果通道
+func startAcquire(t *testing.T, s *Semaphore, ctx context.Context, n int64) <-chan error {
+	t.Helper()
+	before := waiterCount(s)
+	done := make(chan error, 1)
+	go func() { done <- s.Acquire(ctx, n) }()
+	waitFor(t, "the waiter to queue", func() bool { return waiterCount(s) == before+1 })
+	return done
+}
+
+// checkAcquired 检查哪些等待者已经获取成功：acquired中的必须成功返回，其余的必须还在等待
+func checkAcquired(t *testing.T, waiters map[string]<-chan error, acquired ...string) {
+	t.Helper()
+	for _, name := range acquired {
+		if err := <-waiters[name]; err != nil {
+			t.Fatalf("waiter %s: Acquire() = %v", name, err)
+		}
+		delete(waiters, name)
+	}
+	// Release在持锁时同步唤醒等待者，返回后没被唤醒的一定还在等待
+	for name, done := range waiters {
+		select {
+		case err := <-done:
+			t.Fatalf("waiter %s returned %v out of turn", name, err)
+		default:
+		}
+	}
+}
+
+func TestSemaphoreFIFO(t *testing.T) {
+	s := NewSemaphore(10)
+	if err := s.Acquire(context.Background(), 10); err != nil {
+		t.Fatal(err)
+	}
+	waiters := map[string]<-chan error{}
+	for _, w := range []struct {
+		name string
+		n    int64
+	}{{"A", 6}, {"B", 2}, {"C", 3}, {"D", 1}} {
+		waiters[w.name] = startAcquire(t, s, context.Background(), w.n)
+	}
+
+	// 空闲4个，队首A需要6个，后面的B虽然够用也不能插队
+	s.Release(4)
+	checkAcquired(t, waiters)
+	if s.TryAcquire(1) {
+		t.Error("TryAcquire succeeded while others are waiting")
+	}
+	s.Release(2)
+	checkAcquired(t, waiters, "A")
+	s.Release(5)
+	checkAcquired(t, waiters, "B", "C")
+	s.Release(1)
+	checkAcquired(t, waiters, "D")
+
+	if s.cur != 10 || waiterCount(s) != 0 {
+		t.Errorf("cur = %d with %d waiters, want 10 and 0", s.cur, waiterCount(s))
+	}
+}
+
+func TestSemaphoreCancelWhileWaiting(t *testing.T) {
+	t.Run("取消的等待者不占用资源", func(t *testing.T) {
+		s := NewSemaphore(5)
+		s.Acquire(context.Background(), 5)
+		ctx, cancel := context.WithCancel(context.Background())
+		waiters := map[string]<-chan error{
+			"A": startAcquire(t, s, ctx, 3),
+		}
+		waiters["B"] = startAcquire(t, s, context.Background(), 1)
+		cancel()
+		if err := <-waiters["A"]; !errors.Is(err, context.Canceled) {
+			t.Fatalf("cancelled Acquire() = %v", err)
+		}
+		delete(waiters, "A")
+		checkAcquired(t, waiters)
+		s.Release(1)
+		checkAcquired(t, waiters, "B")
+		if s.cur != 5 {
+			t.Errorf("cur = %d, want 5", s.cur)
+		}
+	})
+
+	t.Run("队首取消后唤醒后面的等待者", func(t *testing.T) {
+		s := NewSemaphore(5)
+		s.Acquire(context.Background(), 4)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		waiters := map[string]<-chan error{
+			"A": startAcquire(t, s, ctx, 3),
+		}
+		waiters["B"] = startAcquire(t, s, context.Background(), 1)
+		if err := <-waiters["A"]; !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("timed out Acquire() = %v", err)
+		}
+		delete(waiters, "A")
+		checkAcquired(t, waiters, "B")
+		if s.cur != 5 {
+			t.Errorf("cur = %d, want 5", s.cur)
+		}
+	})
+
+	t.Run("取消与获取同时发生", func(t *testing.T) {
+		// 无论哪一方先发生，返回错误时都不能占用资源
+		s := NewSemaphore(1)
+		for range 300 {
+			s.Acquire(context.Background(), 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := startAcquire(t, s, ctx, 1)
+			go cancel()
+			s.Release(1)
+			if err := <-done; err == nil {
+				s.Release(1)
+			}
+			if !s.TryAcquire(1) {
+				t.Fatalf("resources leaked: cur = %d", s.cur)
+			}
+			s.Release(1)
+		}
+	})
+}
+
+func TestSemaphoreLimits(t *testing.T) {
+	s := NewSemaphore(3)
+	if err := s.Acquire(context.Background(), 4); !errors.Is(err, ErrWeightTooLarge) {
+		t.Errorf("Acquire(4) = %v, want ErrWeightTooLarge", err)
+	}
+	if err := s.Acquire(context.Background(), 3); err != nil {
+		t.Errorf("Acquire of the full size = %v", err)
+	}
+	if err := s.Acquire(context.Background(), 0); err != nil {
+		t.Errorf("Acquire(0) = %v", err)
+	}
+	if s.TryAcquire(1) {
+		t.Error("TryAcquire on a full semaphore returned true")
+	}
+
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"容量为0", func() { NewSemaphore(0) }},
+		{"获取负数", func() { s.Acquire(context.Background(), -1) }},
+		{"释放负数", func() { s.Release(-1) }},
+		{"释放过多", func() { s.Release(4) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", tt.name)
+				}
+			}()
+			tt.call()
+		})
+	}
+}
+
+func TestSemaphoreConcurrent(t *testing.T) {
+	// 随机的获取、超时和释放交错进行，占用量始终不超过容量，结束后没有泄漏
+	s := NewSemaphore(10)
+	var inUse atomic.Int64
+	var wg sync.WaitGroup
+	for g := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(g)))
+			for range 300 {
+				n := int64(r.Intn(11))
+				var ok bool
+				if r.Intn(4) == 0 {
+					ok = s.TryAcquire(n)
+				} else {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.Intn(200))*time.Microsecond)
+					ok = s.Acquire(ctx, n) == nil
+					cancel()
+				}
+				if !ok {
+					continue
+				}
+				if cur := inUse.Add(n); cur > 10 {
+					t.Errorf("%d in use, size is 10", cur)
+				}
+				time.Sleep(time.Duration(r.Intn(50)) * time.Microsecond)
+				inUse.Add(-n)
+				s.Release(n)
+			}
+		}()
+	}
+	wg.Wait()
+	if !s.TryAcquire(10) {
+		t.Fatalf("cur = %d with %d waiters after all releases", s.cur, waiterCount(s))
+	}
+}
+
+func TestGroupSetLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+	var running, peak atomic.Int64
+	for range 20 {
+		g.Go(func() error {
+			cur := running.Add(1)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("peak concurrency %d, want 2", peak.Load())
+	}
+
+	// 达到限制时TryGo不启动新的goroutine
+	g.SetLimit(1)
+	release := make(chan struct{})
+	if !g.TryGo(func() error { <-release; return nil }) {
+		t.Fatal("TryGo below the limit returned false")
+	}
+	if g.TryGo(func() error { return nil }) {
+		t.Error("TryGo at the limit returned true")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("SetLimit with running goroutines did not panic")
+			}
+		}()
+		g.SetLimit(3)
+	}()
+	close(release)
+	g.Wait()
+
+	// 负数取消限制
+	g.SetLimit(-1)
+	block := make(chan struct{})
+	for range 10 {
+		if !g.TryGo(func() error { <-block; return nil }) {
+			t.Fatal("TryGo without a limit returned false")
+		}
+	}
+	close(block)
+	g.Wait()
+}
+
+func TestGroupErrors(t *testing.T) {
+	t.Run("第一个错误取消context", func(t *testing.T) {
+		errFirst := errors.New("first")
+		g, ctx := NewGroup(context.Background())
+		g.Go(func() error { return errFirst })
+		for i := range 3 {
+			g.Go(func() error {
+				<-ctx.Done()
+				return fmt.Errorf("task %d: %w", i, ctx.Err())
+			})
+		}
+		g.Go(func() error { return nil })
+		err := g.Wait()
+		// Wait返回所有错误，context.Cause返回第一个
+		if !errors.Is(err, errFirst) || !errors.Is(err, context.Canceled) {
+			t.Errorf("Wait() = %v", err)
+		}
+		if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 4 {
+			t.Errorf("Wait() joined %d errors, want 4", n)
+		}
+		if cause := context.Cause(ctx); cause != errFirst {
+			t.Errorf("context.Cause() = %v, want %v", cause, errFirst)
+		}
+	})
+
+	t.Run("没有错误", func(t *testing.T) {
+		g, ctx := NewGroup(context.Background())
+		g.Go(func() error { return nil })
+		if err := g.Wait(); err != nil {
+			t.Errorf("Wait() = %v", err)
+		}
+		// Wait返回后context也被取消
+		if ctx.Err() == nil || context.Cause(ctx) != context.Canceled {
+			t.Errorf("after Wait: ctx.Err() = %v, Cause = %v", ctx.Err(), context.Cause(ctx))
+		}
+	})
+
+	t.Run("零值收集所有错误", func(t *testing.T) {
+		var g Group
+		var errs []error
+		for i := range 5 {
+			errs = append(errs, fmt.Errorf("task %d", i))
+		}
+		for _, err := range errs {
+			g.Go(func() error { return err })
+		}
+		got := g.Wait()
+		for _, err := range errs {
+			if !errors.Is(got, err) {
+				t.Errorf("Wait() = %v, missing %v", got, err)
+			}
+		}
+	})
+}
@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -373,39 +374,74 @@ func demonstrateContext() {
 
 // 12. 并发模式 - Worker Pool
 // Worker Pool是一种常见的并发模式，用于限制并发goroutine的数量
+// 可复用的泛型实现见worker_pool.go中的WorkerPool
 
 func demonstrateWorkerPool() {
 	fmt.Println("\n=== Worker Pool 演示 ===")
 
-	const numWorkers = 3
-	const numJobs = 10
+	// 3个worker处理10个任务，通过结果通道收集结果
+	pool := NewWorkerPool(context.Background(), func(ctx context.Context, j int) (int, error) {
+		time.Sleep(time.Duration(rand.Intn(100)) * time.Millisecond)
+		if j == 7 {
+			panic("任务7数据损坏")
+		}
+		if j%5 == 4 {
+			return 0, fmt.Errorf("任务%d参数无效", j)
+		}
+		return j * 2, nil
+	}, WorkerPoolOptions[int, int]{Workers: 3, QueueSize: 10})
 
-	// 创建任务通道和结果通道
-	jobs := make(chan int, numJobs)
-	results := make(chan int, numJobs)
+	go func() {
+		for j := 0; j < 10; j++ {
+			pool.Submit(context.Background(), j)
+		}
+		pool.Shutdown(context.Background())
+	}()
+	for r := range pool.Results() {
+		if r.Err != nil {
+			fmt.Printf("任务 %d 失败: %v\n", r.Input, r.Err)
+		} else {
+			fmt.Printf("任务 %d 的结果: %d\n", r.Input, r.Output)
+		}
+	}
+	fmt.Printf("统计: %+v\n", pool.Stats())
 
-	// 启动worker
-	for w := 0; w < numWorkers; w++ {
-		go func(id int) {
-			for j := range jobs {
-				fmt.Printf("Worker %d 开始处理任务 %d\n", id, j)
-				time.Sleep(time.Duration(rand.Intn(1000)) * time.Millisecond)
-				results <- j * 2 // 假设处理结果是输入的两倍
-				fmt.Printf("Worker %d 完成任务 %d\n", id, j)
+	// 使用回调接收结果，池的context超时后排队的任务不再执行
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	var succeeded atomic.Int64
+	slow := NewWorkerPool(ctx, func(ctx context.Context, d time.Duration) (string, error) {
+		select {
+		case <-time.After(d):
+			return fmt.Sprintf("耗时%v", d), nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}, WorkerPoolOptions[time.Duration, string]{
+		Workers:   2,
+		QueueSize: 20,
+		OnResult: func(r JobResult[time.Duration, string]) {
+			if r.Err == nil {
+				succeeded.Add(1)
 			}
-		}(w)
-	}
+		},
+	})
 
-	// 发送任务
-	for j := 0; j < numJobs; j++ {
-		jobs <- j
+	if out, err := slow.SubmitWait(context.Background(), 50*time.Millisecond); err == nil {
+		fmt.Printf("SubmitWait: %s\n", out)
 	}
-	close(jobs)
-
-	// 收集结果
-	for r := 0; r < numJobs; r++ {
-		result := <-results
-		fmt.Printf("收到结果: %d\n", result)
+	for i := 0; i < 10; i++ {
+		slow.Submit(context.Background(), 100*time.Millisecond)
+	}
+	time.Sleep(120 * time.Millisecond)
+	fmt.Printf("执行中: %+v\n", slow.Stats())
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Second)
+	defer cancelShutdown()
+	err := slow.Shutdown(shutdownCtx)
+	fmt.Printf("关闭后: 成功 %d 个, %+v, Shutdown错误: %v\n", succeeded.Load(), slow.Stats(), err)
+	if err := slow.Submit(context.Background(), time.Millisecond); errors.Is(err, ErrPoolClosed) {
+		fmt.Printf("关闭后提交: %v\n", err)
 	}
 }
 
@@ -463,6 +499,8 @@ func main() {
 	demonstrateContext()
 	demonstrateWorkerPool()
 	demonstrateConcurrentDataStructure()
+	demonstrateSemaphore()
+	demonstrateGroup()
 
 	var counter int
 	var wait sync.WaitGroup
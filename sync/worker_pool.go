@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// 14. 泛型Worker Pool
+// 把第12节的worker pool模式封装成可复用的类型：输入输出类型、worker数量和队列长度都可以配置
+// 每个任务的panic会被恢复并作为错误返回，不会影响其他任务
+// 池的context被取消后，正在执行的任务通过ctx感知取消，还在队列中的任务不再执行，直接以ctx.Err()作为结果
+
+// ErrPoolClosed 向已经关闭的worker pool提交任务
+var ErrPoolClosed = errors.New("worker pool已关闭")
+
+// PanicError 任务中发生的panic被转换成的错误
+type PanicError struct {
+	Value any    // recover()得到的值
+	Stack []byte // 发生panic时的堆栈
+}
+
+// Error 实现error接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("任务发生panic: %v", e.Value)
+}
+
+// JobResult 一个任务的执行结果
+type JobResult[In, Out any] struct {
+	Input  In
+	Output Out
+	Err    error
+}
+
+// WorkerPoolOptions worker pool配置
+type WorkerPoolOptions[In, Out any] struct {
+	Workers   int                      // worker数量，<=0时使用GOMAXPROCS
+	QueueSize int                      // 等待队列长度，0表示提交时必须有空闲worker接收
+	OnResult  func(JobResult[In, Out]) // 结果回调，在worker中调用；设置后Results()返回nil
+}
+
+// WorkerPoolStats worker pool的实时统计
+type WorkerPoolStats struct {
+	Queued    int64 // 在队列中等待的任务数
+	Running   int64 // 正在执行的任务数
+	Completed int64 // 成功完成的任务数
+	Failed    int64 // 返回错误、发生panic或因取消而未执行的任务数
+}
+
+// poolJob 队列中的任务，done不为nil时结果只发送给SubmitWait的调用者
+type poolJob[In, Out any] struct {
+	input In
+	done  chan JobResult[In, Out]
+}
+
+// WorkerPool 固定数量worker的泛型任务池
+type WorkerPool[In, Out any] struct {
+	fn      func(context.Context, In) (Out, error)
+	opts    WorkerPoolOptions[In, Out]
+	ctx     context.Context
+	cancel  context.CancelFunc
+	jobs    chan poolJob[In, Out]
+	results chan JobResult[In, Out]
+
+	mu         sync.Mutex
+	closed     bool
+	quit       chan struct{}  // Shutdown开始时关闭，唤醒阻塞在队列上的Submit
+	submitting sync.WaitGroup // 正在向队列发送的Submit，全部返回后才能关闭队列
+	workers    sync.WaitGroup
+	stopped    chan struct{} // 所有worker退出后关闭
+
+	queued, running, completed, failed atomic.Int64
+}
+
+// NewWorkerPool 创建并启动worker pool，ctx被取消时池中的任务随之取消
+func NewWorkerPool[In, Out any](ctx context.Context, fn func(context.Context, In) (Out, error), opts WorkerPoolOptions[In, Out]) *WorkerPool[In, Out] {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.QueueSize < 0 {
+		panic("NewWorkerPool: 队列长度不能为负数")
+	}
+	p := &WorkerPool[In, Out]{
+		fn:      fn,
+		opts:    opts,
+		jobs:    make(chan poolJob[In, Out], opts.QueueSize),
+		quit:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	if opts.OnResult == nil {
+		p.results = make(chan JobResult[In, Out], opts.QueueSize)
+	}
+
+	p.workers.Add(opts.Workers)
+	for range opts.Workers {
+		go p.work()
+	}
+	go func() {
+		p.workers.Wait()
+		if p.results != nil {
+			close(p.results)
+		}
+		p.cancel()
+		close(p.stopped)
+	}()
+	return p
+}
+
+// Results 返回结果通道，所有worker退出后关闭
+// 没有设置OnResult时调用者必须持续读取这个通道，否则worker会阻塞在发送结果上
+func (p *WorkerPool[In, Out]) Results() <-chan JobResult[In, Out] {
+	return p.results
+}
+
+// Submit 提交任务，结果发送到Results()或OnResult
+// 队列已满时阻塞，直到有空位、ctx被取消或池被关闭
+func (p *WorkerPool[In, Out]) Submit(ctx context.Context, input In) error {
+	return p.submit(ctx, poolJob[In, Out]{input: input})
+}
+
+// SubmitWait 提交任务并等待它的结果，这个结果不会再发送到Results()或OnResult
+// ctx在任务完成前被取消时返回ctx.Err()，任务本身仍会继续执行
+func (p *WorkerPool[In, Out]) SubmitWait(ctx context.Context, input In) (Out, error) {
+	var zero Out
+	done := make(chan JobResult[In, Out], 1)
+	if err := p.submit(ctx, poolJob[In, Out]{input: input, done: done}); err != nil {
+		return zero, err
+	}
+	select {
+	case r := <-done:
+		return r.Output, r.Err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+func (p *WorkerPool[In, Out]) submit(ctx context.Context, job poolJob[In, Out]) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	p.submitting.Add(1)
+	p.mu.Unlock()
+	defer p.submitting.Done()
+
+	// 先计数再入队，避免worker取走任务时计数短暂变成负数
+	p.queued.Add(1)
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		return ctx.Err()
+	case <-p.quit:
+		p.queued.Add(-1)
+		return ErrPoolClosed
+	}
+}
+
+// work worker主循环：队列关闭并且取空后退出
+func (p *WorkerPool[In, Out]) work() {
+	defer p.workers.Done()
+	for job := range p.jobs {
+		p.queued.Add(-1)
+		r := JobResult[In, Out]{Input: job.input}
+		if err := p.ctx.Err(); err != nil {
+			r.Err = err
+		} else {
+			p.running.Add(1)
+			r.Output, r.Err = p.call(job.input)
+			p.running.Add(-1)
+		}
+		if r.Err != nil {
+			p.failed.Add(1)
+		} else {
+			p.completed.Add(1)
+		}
+
+		switch {
+		case job.done != nil:
+			job.done <- r
+		case p.opts.OnResult != nil:
+			p.opts.OnResult(r)
+		default:
+			p.results <- r
+		}
+	}
+}
+
+// call 执行任务，把panic转换成PanicError
+func (p *WorkerPool[In, Out]) call(input In) (out Out, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return p.fn(p.ctx, input)
+}
+
+// Stats 返回当前的统计信息
+func (p *WorkerPool[In, Out]) Stats() WorkerPoolStats {
+	return WorkerPoolStats{
+		Queued:    p.queued.Load(),
+		Running:   p.running.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+	}
+}
+
+// Shutdown 停止接收新任务，等待队列中和正在执行的任务全部完成
+// ctx先结束时取消池的context（剩下的排队任务不再执行）并返回ctx.Err()，结果通道在worker全部退出后关闭
+// 可以多次调用
+func (p *WorkerPool[In, Out]) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.quit)
+		p.mu.Unlock()
+		// 正在阻塞的Submit会因为quit返回，之后不会再有任务入队
+		p.submitting.Wait()
+		close(p.jobs)
+	} else {
+		p.mu.Unlock()
+	}
+
+	select {
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
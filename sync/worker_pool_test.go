@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor 轮询直到cond成立，超时则测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// double 把输入乘以2的任务函数
+func double(_ context.Context, n int) (int, error) {
+	time.Sleep(100 * time.Microsecond)
+	return n * 2, nil
+}
+
+func TestWorkerPoolShutdownDrains(t *testing.T) {
+	p := NewWorkerPool(context.Background(), double, WorkerPoolOptions[int, int]{Workers: 2, QueueSize: 10})
+	var outputs []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for r := range p.Results() {
+			if r.Err != nil || r.Output != r.Input*2 {
+				t.Errorf("result %+v", r)
+			}
+			outputs = append(outputs, r.Output)
+		}
+	}()
+
+	for i := range 50 {
+		if err := p.Submit(context.Background(), i); err != nil {
+			t.Fatalf("Submit(%d) = %v", i, err)
+		}
+	}
+	// Shutdown等待队列中的任务全部执行完，Results随后关闭
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	<-done
+	slices.Sort(outputs)
+	for i, v := range outputs {
+		if v != i*2 {
+			t.Fatalf("outputs = %v", outputs)
+		}
+	}
+	if len(outputs) != 50 {
+		t.Fatalf("got %d results, want 50", len(outputs))
+	}
+	if s := p.Stats(); s != (WorkerPoolStats{Completed: 50}) {
+		t.Errorf("Stats() = %+v", s)
+	}
+
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Shutdown = %v", err)
+	}
+	if _, err := p.SubmitWait(context.Background(), 1); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("SubmitWait after Shutdown = %v", err)
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown() = %v", err)
+	}
+}
+
+func TestWorkerPoolPanicRecovery(t *testing.T) {
+	var mu sync.Mutex
+	results := map[int]JobResult[int, int]{}
+	p := NewWorkerPool(context.Background(), func(_ context.Context, n int) (int, error) {
+		if n%3 == 0 {
+			panic("bad input")
+		}
+		if n%3 == 1 {
+			return 0, errors.New("failed")
+		}
+		return n, nil
+	}, WorkerPoolOptions[int, int]{
+		Workers: 3,
+		OnResult: func(r JobResult[int, int]) {
+			mu.Lock()
+			results[r.Input] = r
+			mu.Unlock()
+		},
+	})
+	if p.Results() != nil {
+		t.Error("Results() is not nil when OnResult is set")
+	}
+	for i := range 30 {
+		p.Submit(context.Background(), i)
+	}
+	p.Shutdown(context.Background())
+
+	if len(results) != 30 {
+		t.Fatalf("got %d results, want 30", len(results))
+	}
+	for i, r := range results {
+		var pe *PanicError
+		switch i % 3 {
+		case 0:
+			// panic不会导致worker退出，而是转换成带堆栈的错误
+			if !errors.As(r.Err, &pe) || pe.Value != "bad input" || len(pe.Stack) == 0 {
+				t.Errorf("job %d: Err = %v, want PanicError", i, r.Err)
+			}
+		case 1:
+			if r.Err == nil || errors.As(r.Err, &pe) {
+				t.Errorf("job %d: Err = %v, want plain error", i, r.Err)
+			}
+		default:
+			if r.Err != nil || r.Output != i {
+				t.Errorf("job %d: %+v", i, r)
+			}
+		}
+	}
+	if s := p.Stats(); s.Completed != 10 || s.Failed != 20 {
+		t.Errorf("Stats() = %+v", s)
+	}
+}
+
+func TestWorkerPoolStats(t *testing.T) {
+	release := make(chan struct{})
+	var completed atomic.Int64
+	p := NewWorkerPool(context.Background(), func(_ context.Context, n int) (int, error) {
+		<-release
+		return n, nil
+	}, WorkerPoolOptions[int, int]{
+		Workers:   2,
+		QueueSize: 3,
+		OnResult:  func(JobResult[int, int]) { completed.Add(1) },
+	})
+
+	for i := range 2 {
+		p.Submit(context.Background(), i)
+	}
+	waitFor(t, "two running jobs", func() bool { return p.Stats().Running == 2 })
+	for i := range 3 {
+		p.Submit(context.Background(), i)
+	}
+	if s := p.Stats(); s != (WorkerPoolStats{Queued: 3, Running: 2}) {
+		t.Errorf("with full queue: Stats() = %+v", s)
+	}
+
+	// 队列已满时Submit阻塞，直到ctx超时
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Submit(ctx, 99); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit to a full queue = %v", err)
+	}
+	if q := p.Stats().Queued; q != 3 {
+		t.Errorf("Queued = %d after a failed Submit, want 3", q)
+	}
+
+	close(release)
+	p.Shutdown(context.Background())
+	if s := p.Stats(); s != (WorkerPoolStats{Completed: 5}) || completed.Load() != 5 {
+		t.Errorf("after Shutdown: Stats() = %+v, %d callbacks", s, completed.Load())
+	}
+}
+
+func TestWorkerPoolSubmitWait(t *testing.T) {
+	var callbacks atomic.Int64
+	p := NewWorkerPool(context.Background(), func(ctx context.Context, n int) (int, error) {
+		if n < 0 {
+			return 0, errors.New("negative")
+		}
+		if n == 0 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return n * 10, nil
+	}, WorkerPoolOptions[int, int]{
+		Workers:  2,
+		OnResult: func(JobResult[int, int]) { callbacks.Add(1) },
+	})
+
+	if v, err := p.SubmitWait(context.Background(), 4); v != 40 || err != nil {
+		t.Errorf("SubmitWait(4) = %d, %v", v, err)
+	}
+	if _, err := p.SubmitWait(context.Background(), -1); err == nil || err.Error() != "negative" {
+		t.Errorf("SubmitWait(-1) = %v", err)
+	}
+	// 等待结果时ctx被取消，SubmitWait立即返回，任务仍在执行
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.SubmitWait(ctx, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SubmitWait with expired ctx = %v", err)
+	}
+	if r := p.Stats().Running; r != 1 {
+		t.Errorf("Running = %d, want the abandoned job still running", r)
+	}
+
+	// Shutdown的ctx先结束时取消池的context，阻塞的任务随之返回
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShutdown()
+	if err := p.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v", err)
+	}
+	p.Shutdown(context.Background())
+	if callbacks.Load() != 0 {
+		t.Errorf("SubmitWait results were also sent to OnResult %d times", callbacks.Load())
+	}
+	if s := p.Stats(); s.Completed != 1 || s.Failed != 2 || s.Running != 0 {
+		t.Errorf("Stats() = %+v", s)
+	}
+}
+
+func TestWorkerPoolCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	var calls atomic.Int64
+	p := NewWorkerPool(ctx, func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+		if n == 0 {
+			close(started)
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, WorkerPoolOptions[int, int]{Workers: 1, QueueSize: 5})
+
+	var errs []error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for r := range p.Results() {
+			errs = append(errs, r.Err)
+		}
+	}()
+	for i := range 5 {
+		p.Submit(context.Background(), i)
+	}
+	<-started
+	cancel()
+	p.Shutdown(context.Background())
+	<-done
+
+	// 正在执行的任务通过ctx感知取消，排队的任务不再执行
+	if calls.Load() != 1 {
+		t.Errorf("fn called %d times, want 1", calls.Load())
+	}
+	if len(errs) != 5 {
+		t.Fatalf("got %d results, want 5", len(errs))
+	}
+	for _, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("result error = %v, want context.Canceled", err)
+		}
+	}
+	if s := p.Stats(); s != (WorkerPoolStats{Failed: 5}) {
+		t.Errorf("Stats() = %+v", s)
+	}
+}
+
+func TestWorkerPoolConcurrentSubmitAndShutdown(t *testing.T) {
+	// 提交和关闭并发进行时，每个成功提交的任务恰好产生一个结果，配合go test -race运行
+	for i := range 100 {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := NewWorkerPool(ctx, func(_ context.Context, n int) (int, error) {
+			if n%13 == 0 {
+				panic(n)
+			}
+			return n, nil
+		}, WorkerPoolOptions[int, int]{Workers: i%4 + 1, QueueSize: i % 3})
+
+		var received atomic.Int64
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range p.Results() {
+				received.Add(1)
+			}
+		}()
+
+		var submitted, waited atomic.Int64
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 50 {
+					if j%5 == 0 {
+						if _, err := p.SubmitWait(context.Background(), j); !errors.Is(err, ErrPoolClosed) {
+							waited.Add(1)
+						}
+					} else if p.Submit(context.Background(), j) == nil {
+						submitted.Add(1)
+					}
+				}
+			}()
+		}
+		if i%2 == 0 {
+			cancel()
+		}
+		p.Shutdown(context.Background())
+		wg.Wait()
+		<-done
+		cancel()
+
+		s := p.Stats()
+		if received.Load() != submitted.Load() || s.Completed+s.Failed != submitted.Load()+waited.Load() || s.Queued != 0 || s.Running != 0 {
+			t.Fatalf("round %d: received %d of %d submitted, %d waited, Stats() = %+v",
+				i, received.Load(), submitted.Load(), waited.Load(), s)
+		}
+	}
+}